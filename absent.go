@@ -4,6 +4,8 @@
 
 package kv
 
+import "context"
+
 var (
 	// ErrNil is the error that returns when a value is not nil.
 	ErrNil = NewError("validation_nil", "must be blank")
@@ -44,6 +46,11 @@ func (r EmptyRule[T]) When(condition bool) EmptyRule[T] {
 	return r
 }
 
+func (r EmptyRule[T]) conditionFalse(context.Context, any) bool {
+	// condition is true when the validation is turned off
+	return r.condition
+}
+
 // Error sets the error message for the rule.
 func (r EmptyRule[T]) Error(message string) EmptyRule[T] {
 	if r.err == nil {
@@ -86,6 +93,11 @@ func (r NilRule[T]) When(condition bool) NilRule[T] {
 	return r
 }
 
+func (r NilRule[T]) conditionFalse(context.Context, any) bool {
+	// condition is true when the validation is turned off
+	return r.condition
+}
+
 // Error sets the error message for the rule.
 func (r NilRule[T]) Error(message string) NilRule[T] {
 	if r.err == nil {
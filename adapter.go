@@ -0,0 +1,119 @@
+package kv
+
+import (
+	"context"
+	"reflect"
+)
+
+// ErrTypeMismatch is the error that returns when a value is not of the type expected by a typed rule.
+var ErrTypeMismatch = NewError("validation_type_mismatch", "must be a value of type {{.type}}")
+
+// Any adapts a rule for values of type T into a rule for values of any type, so that typed rules
+// can be mixed with other rules in Validate, Field and Key. For example,
+//
+//	kv.Field(&c.Age, kv.Required, kv.Any(kv.Min(18)))
+//
+// The value being validated is converted to T with a type assertion. If the assertion fails,
+// pointers and driver.Valuer values are resolved the same way Indirect does and the assertion is tried again.
+// A nil value is validated as the zero value of T. If the value is not of type T, ErrTypeMismatch is returned.
+func Any[T any](rule Rule[T]) Rule[any] {
+	if r, ok := rule.(typedRule[T]); ok {
+		return r.rule
+	}
+	return anyRule[T]{rule: rule}
+}
+
+// Typed adapts a rule for values of any type into a rule for values of type T, so that existing rules
+// can be used with ValidateValue. For example,
+//
+//	kv.ValidateValue(email, kv.Typed[string](kv.Required), kv.Typed[string](is.Email))
+func Typed[T any](rule Rule[any]) Rule[T] {
+	if r, ok := rule.(anyRule[T]); ok {
+		return r.rule
+	}
+	return typedRule[T]{rule: rule}
+}
+
+type anyRule[T any] struct {
+	rule Rule[T]
+}
+
+// Validate converts the value to T and validates it using the adapted rule.
+func (r anyRule[T]) Validate(value any) error {
+	v, err := toType[T](value)
+	if err != nil {
+		return err
+	}
+	return r.rule.Validate(v)
+}
+
+// ValidateWithContext converts the value to T and validates it with the given context using the adapted rule.
+func (r anyRule[T]) ValidateWithContext(ctx context.Context, value any) error {
+	v, err := toType[T](value)
+	if err != nil {
+		return err
+	}
+	return validateRule(ctx, r.rule, v)
+}
+
+func (r anyRule[T]) skipped(ctx context.Context, value any) bool {
+	s, ok := r.rule.(skipper[T])
+	if !ok {
+		return false
+	}
+	// a value that is not of type T does not skip, so that the adapted rule reports the mismatch
+	v, err := toType[T](value)
+	return err == nil && s.skipped(ctx, v)
+}
+
+func (r anyRule[T]) transformer() (Transformer, bool) {
+	return transformerOf(r.rule)
+}
+
+func (r anyRule[T]) adapted() any {
+	return r.rule
+}
+
+type typedRule[T any] struct {
+	rule Rule[any]
+}
+
+// Validate validates the value using the adapted rule.
+func (r typedRule[T]) Validate(value T) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateWithContext validates the value with the given context using the adapted rule.
+func (r typedRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	return validateRule(ctx, r.rule, any(value))
+}
+
+func (r typedRule[T]) skipped(ctx context.Context, value T) bool {
+	s, ok := r.rule.(skipper[any])
+	return ok && s.skipped(ctx, value)
+}
+
+func (r typedRule[T]) transformer() (Transformer, bool) {
+	return transformerOf(r.rule)
+}
+
+func (r typedRule[T]) adapted() any {
+	return r.rule
+}
+
+// toType converts a value to T, resolving pointers and driver.Valuer values if needed.
+func toType[T any](value any) (T, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+
+	var zero T
+	value, isNil := Indirect(value)
+	if isNil {
+		return zero, nil
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	return zero, ErrTypeMismatch.AddParam("type", reflect.TypeFor[T]().String())
+}
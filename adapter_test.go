@@ -0,0 +1,90 @@
+package kv
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestAny(t *testing.T) {
+	n := int64(5)
+	var nilPtr *int64
+	tests := []struct {
+		tag   string
+		value any
+		err   string
+	}{
+		{"t1", int64(1), ""},
+		{"t2", int64(5), ""},
+		{"t3", int64(20), "must be no greater than 10"},
+		{"t4", &n, ""},
+		{"t5", nilPtr, ""},
+		{"t6", nil, ""},
+		{"t7", sql.NullInt64{Int64: 20, Valid: true}, "must be no greater than 10"},
+		{"t8", sql.NullInt64{}, ""},
+		{"t9", "abc", "must be a value of type int64"},
+		{"t10", 5, "must be a value of type int64"},
+	}
+
+	rule := Any(Max(int64(10)))
+	for _, test := range tests {
+		err := rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		err = ValidateWithContext(context.Background(), test.value, rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := rule.Validate("abc")
+	if e, ok := err.(Error); assert.True(t, ok) {
+		assert.Equal(t, "validation_type_mismatch", e.Code())
+	}
+
+	// mixing typed and untyped rules
+	assert.EqualError(t, Validate(0, Required, Any(Min(1))), "cannot be blank")
+	assert.EqualError(t, Validate(-1, Required, Any(Min(1))), "must be no less than 1")
+}
+
+func TestAnyWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	rule := Any[string](typedContainsRule{})
+
+	assert.NoError(t, ValidateWithContext(ctx, "abc", rule))
+	assert.EqualError(t, ValidateWithContext(ctx, "xyz", rule), "unexpected value")
+	assert.EqualError(t, Validate("abc", rule), "no context")
+}
+
+func TestTyped(t *testing.T) {
+	rule := Typed[string](Required)
+	assert.NoError(t, rule.Validate("abc"))
+	assert.EqualError(t, rule.Validate(""), "cannot be blank")
+	assert.EqualError(t, ValidateValue("", Typed[string](Required), Typed[string](Length(2, 5))), "cannot be blank")
+	assert.EqualError(t, ValidateValue("a", Typed[string](Required), Typed[string](Length(2, 5))), "the length must be between 2 and 5")
+
+	// skip survives the adapter
+	assert.NoError(t, ValidateValue("", Typed[string](Skip), Typed[string](Required)))
+	assert.EqualError(t, ValidateValue("", Typed[string](Skip.When(false)), Typed[string](Required)), "cannot be blank")
+	assert.NoError(t, Validate("", Any(Typed[string](Skip)), Required))
+}
+
+func TestTypedWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	rule := Typed[string](WithContext(func(ctx context.Context, value any) error {
+		if ctx.Value(contains) != value {
+			return errors.New("unexpected value")
+		}
+		return nil
+	}))
+
+	assert.NoError(t, ValidateValueWithContext(ctx, "abc", rule))
+	assert.EqualError(t, ValidateValueWithContext(ctx, "xyz", rule), "unexpected value")
+}
+
+func TestAnyTypedRoundTrip(t *testing.T) {
+	_, ok := Typed[int](Any[int](Min(1))).(ThresholdRule[int])
+	assert.True(t, ok)
+	_, ok = Any(Typed[string](Required)).(RequiredRule)
+	assert.True(t, ok)
+}
@@ -0,0 +1,403 @@
+package kv
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrTransitionType is the error that a value cannot be compared by a transition rule because of its type.
+	ErrTransitionType = errors.New("the value cannot be compared by the transition rule because of its type")
+
+	// ErrImmutable is the error that returns when a value is changed while it is immutable.
+	ErrImmutable = NewError("validation_immutable", "cannot be changed")
+	// ErrOneWay is the error that returns when a value is changed from a value it may not be changed from.
+	ErrOneWay = NewError("validation_one_way", "cannot be changed from {{.value}}")
+	// ErrTransition is the error that returns when a value is changed along a transition that is not allowed.
+	ErrTransition = NewError("validation_transition", "cannot change from {{.from}} to {{.to}}")
+	// ErrIncreasing is the error that returns when a value decreases while it may only increase.
+	ErrIncreasing = NewError("validation_increasing", "cannot be decreased")
+	// ErrDecreasing is the error that returns when a value increases while it may only decrease.
+	ErrDecreasing = NewError("validation_decreasing", "cannot be increased")
+)
+
+// Immutable is a transition rule that checks if a value is not changed.
+// Times are compared with time.Time.Equal, and the values referenced by pointers are compared instead of the pointers.
+var Immutable = ImmutableRule{err: ErrImmutable}
+
+var (
+	// Increasing is a transition rule that checks if a number, a string or a time is not decreased.
+	// A value that is nil before or after the change is not checked.
+	Increasing = MonotonicRule{increasing: true, err: ErrIncreasing}
+	// Decreasing is a transition rule that checks if a number, a string or a time is not increased.
+	// A value that is nil before or after the change is not checked.
+	Decreasing = MonotonicRule{err: ErrDecreasing}
+)
+
+type (
+	// TransitionRule represents a rule that validates the change of a value from its old value to its new value.
+	TransitionRule interface {
+		// ValidateTransition validates the change of a value and returns an error if the change is not allowed.
+		ValidateTransition(old, new any) error
+	}
+
+	// TransitionRuleWithContext represents a transition rule that needs the context of the validation.
+	TransitionRuleWithContext interface {
+		// ValidateTransitionWithContext validates the change of a value with the given context.
+		ValidateTransitionWithContext(ctx context.Context, old, new any) error
+	}
+
+	// ChangeRules represents a set of transition rules associated with a struct field.
+	ChangeRules struct {
+		fieldPtr any
+		rules    []TransitionRule
+	}
+
+	// ImmutableRule is a transition rule that checks if a value is not changed.
+	ImmutableRule struct {
+		err Error
+	}
+
+	// OneWayRule is a transition rule that checks if a value is only changed from the specified values.
+	OneWayRule struct {
+		values []any
+		err    Error
+	}
+
+	// TransitionsRule is a transition rule that checks if a value is changed along the allowed edges of a state machine.
+	TransitionsRule[S comparable] struct {
+		edges map[S][]S
+		err   Error
+	}
+
+	// MonotonicRule is a transition rule that checks if a value only increases or only decreases.
+	MonotonicRule struct {
+		increasing bool
+		err        Error
+	}
+
+	// TransitionFuncRule is a transition rule that validates the change of a value of type T by a function.
+	TransitionFuncRule[T any] struct {
+		f func(ctx context.Context, old, new T) error
+	}
+)
+
+// ValidateChange validates the change of a struct from its old value to its new value by checking the specified
+// struct fields against the corresponding transition rules. Both values must be specified as pointers to the struct.
+// Use Change() to specify the fields, each of which should be specified as a pointer to the field of the new value.
+// For example,
+//
+//	err := kv.ValidateChange(&stored, &order,
+//	    kv.Change(&order.Status, kv.Transitions(map[Status][]Status{
+//	        Draft:  {Review},
+//	        Review: {Draft, Published},
+//	    })),
+//	    kv.Change(&order.CreatedAt, kv.Immutable),
+//	    kv.Change(&order.Quantity, kv.Decreasing),
+//	)
+//	fmt.Println(err)
+//	// Status: cannot change from draft to published.
+//
+// The errors are returned as Errors keyed by the error names of the fields, like ValidateStruct does.
+// If either pointer is nil, there is no change to validate and nil is returned.
+func ValidateChange[T any](old, new *T, fields ...*ChangeRules) error {
+	return ValidateChangeWithContext(context.TODO(), old, new, fields...)
+}
+
+// ValidateChangeWithContext validates the change of a struct with the given context.
+// Please refer to ValidateChange for the detailed instructions on how to use this function.
+func ValidateChangeWithContext[T any](ctx context.Context, old, new *T, fields ...*ChangeRules) error {
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		return NewInternalError(ErrStructPointer)
+	}
+	if old == nil || new == nil {
+		// treat a missing old or new value as no change
+		return nil
+	}
+	oldValue, newValue := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+
+	errs := Errors{}
+	for i, fr := range fields {
+		fv := reflect.ValueOf(fr.fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return NewInternalError(ErrFieldPointer(i))
+		}
+		ft := findStructField(newValue, fv)
+		if ft == nil {
+			return NewInternalError(ErrFieldNotFound(i))
+		}
+		ov, err := oldValue.FieldByIndexErr(ft.Index)
+		if err != nil {
+			// the old value has a nil embedded struct pointer
+			ov = reflect.Zero(ft.Type)
+		}
+		if err := validateTransition(ctx, ov.Interface(), fv.Elem().Interface(), fr.rules); err != nil {
+			if isInternalError(err) {
+				return err
+			}
+			errs[getErrorFieldName(ft)] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return Translate(ctx, errs)
+	}
+	return nil
+}
+
+// Change specifies a struct field and the corresponding transition rules.
+// The struct field must be specified as a pointer to the field of the new value passed to ValidateChange.
+func Change(fieldPtr any, rules ...TransitionRule) *ChangeRules {
+	return &ChangeRules{
+		fieldPtr: fieldPtr,
+		rules:    rules,
+	}
+}
+
+// ValidateTransition checks if the value has not changed.
+func (r ImmutableRule) ValidateTransition(old, new any) error {
+	if valuesEqual(old, new) {
+		return nil
+	}
+	return r.err
+}
+
+// Error sets the error message for the rule.
+func (r ImmutableRule) Error(message string) ImmutableRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ImmutableRule) ErrorObject(err Error) ImmutableRule {
+	r.err = err
+	return r
+}
+
+// OneWayFrom returns a transition rule that checks if a value is only changed when its old value is one of the given values.
+// Once the value has been changed to any other value, it cannot be changed anymore. For example, use
+// OneWayFrom("") to allow a string to be set only once, or OneWayFrom(false) to allow a flag to be turned on but
+// never off. The given values are converted to the type of the field if needed. The old value is given as the
+// "value" param of the error.
+func OneWayFrom(values ...any) OneWayRule {
+	return OneWayRule{values: values, err: ErrOneWay}
+}
+
+// ValidateTransition checks if the change of the value is allowed.
+func (r OneWayRule) ValidateTransition(old, new any) error {
+	if valuesEqual(old, new) {
+		return nil
+	}
+	o, _ := Indirect(old)
+	for _, v := range r.values {
+		if valuesEqual(o, v) {
+			return nil
+		}
+	}
+	return withParam(r.err, "value", o)
+}
+
+// Error sets the error message for the rule.
+func (r OneWayRule) Error(message string) OneWayRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r OneWayRule) ErrorObject(err Error) OneWayRule {
+	r.err = err
+	return r
+}
+
+// Transitions returns a transition rule that checks if a value is changed along the edges of a state machine.
+// The edges map each state to the states it may be changed to, and a value may always stay in the same state.
+// For example,
+//
+//	kv.Transitions(map[string][]string{
+//	    "draft":  {"review"},
+//	    "review": {"draft", "published"},
+//	})
+//
+// The field must be of type S, or of a type with the same underlying kind, such as a named string type.
+// A value that is nil before or after the change is not checked. The old and new states are given as the
+// "from" and "to" params of the error.
+func Transitions[S comparable](edges map[S][]S) TransitionsRule[S] {
+	return TransitionsRule[S]{edges: edges, err: ErrTransition}
+}
+
+// ValidateTransition checks if the change of the value is allowed.
+func (r TransitionsRule[S]) ValidateTransition(old, new any) error {
+	old, oldNil := Indirect(old)
+	new, newNil := Indirect(new)
+	if oldNil || newNil {
+		return nil
+	}
+	from, ok := convertTo[S](old)
+	if !ok {
+		return NewInternalError(ErrTransitionType)
+	}
+	to, ok := convertTo[S](new)
+	if !ok {
+		return NewInternalError(ErrTransitionType)
+	}
+	if from == to {
+		return nil
+	}
+	for _, s := range r.edges[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return r.err.SetParams(map[string]any{"from": from, "to": to})
+}
+
+// Error sets the error message for the rule.
+func (r TransitionsRule[S]) Error(message string) TransitionsRule[S] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r TransitionsRule[S]) ErrorObject(err Error) TransitionsRule[S] {
+	r.err = err
+	return r
+}
+
+// ValidateTransition checks if the change of the value is allowed.
+func (r MonotonicRule) ValidateTransition(old, new any) error {
+	old, oldNil := Indirect(old)
+	new, newNil := Indirect(new)
+	if oldNil || newNil {
+		return nil
+	}
+	c, ok := compareValues(new, old)
+	if !ok {
+		return NewInternalError(ErrTransitionType)
+	}
+	if r.increasing && c < 0 || !r.increasing && c > 0 {
+		return r.err
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r MonotonicRule) Error(message string) MonotonicRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r MonotonicRule) ErrorObject(err Error) MonotonicRule {
+	r.err = err
+	return r
+}
+
+// TransitionFunc returns a transition rule that validates the change of a value of type T by the given function.
+// It replaces the Rule closures capturing the old value, for example,
+//
+//	kv.Change(&order.Total, kv.TransitionFunc(func(ctx context.Context, old, new float64) error {
+//	    if new > old*2 {
+//	        return errors.New("cannot be more than doubled")
+//	    }
+//	    return nil
+//	}))
+//
+// If the field is not of type T, an InternalError is returned.
+func TransitionFunc[T any](f func(ctx context.Context, old, new T) error) TransitionFuncRule[T] {
+	return TransitionFuncRule[T]{f: f}
+}
+
+// ValidateTransition checks if the change of the value is allowed.
+func (r TransitionFuncRule[T]) ValidateTransition(old, new any) error {
+	return r.ValidateTransitionWithContext(context.TODO(), old, new)
+}
+
+// ValidateTransitionWithContext checks if the change of the value is allowed.
+func (r TransitionFuncRule[T]) ValidateTransitionWithContext(ctx context.Context, old, new any) error {
+	o, ok := old.(T)
+	if !ok {
+		return NewInternalError(ErrTransitionType)
+	}
+	n, ok := new.(T)
+	if !ok {
+		return NewInternalError(ErrTransitionType)
+	}
+	return r.f(ctx, o, n)
+}
+
+// validateTransition validates the change of a value against the given rules, stopping at the first failing rule.
+func validateTransition(ctx context.Context, old, new any, rules []TransitionRule) error {
+	for _, rule := range rules {
+		var err error
+		if rc, ok := rule.(TransitionRuleWithContext); ok && ctx != nil {
+			err = rc.ValidateTransitionWithContext(ctx, old, new)
+		} else {
+			err = rule.ValidateTransition(old, new)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valuesEqual checks if two values are equal after dereferencing them.
+// The second value is converted to the type of the first one if the conversion is exact.
+func valuesEqual(value, other any) bool {
+	value, valueNil := Indirect(value)
+	other, otherNil := Indirect(other)
+	if valueNil || otherNil {
+		return valueNil == otherNil
+	}
+	if ov, ok := exactValue(reflect.ValueOf(other), reflect.TypeOf(value)); ok {
+		other = ov.Interface()
+	}
+	c, _ := compareEqual(value, other)
+	return c == 0
+}
+
+// convertTo converts the value to type T if it has the same underlying kind and the conversion is exact.
+func convertTo[T any](value any) (T, bool) {
+	if v, ok := value.(T); ok {
+		return v, true
+	}
+	var zero T
+	v, ok := exactValue(reflect.ValueOf(value), reflect.TypeFor[T]())
+	if !ok {
+		return zero, false
+	}
+	return v.Interface().(T), true
+}
+
+// exactValue converts the value to type t like assignableValue does, and reports false if a number is rounded
+// by the conversion, so that values of different types are only considered equal if they represent the same value.
+func exactValue(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	cv, ok := assignableValue(v, t)
+	if ok && isNumber(v.Kind()) && isNumber(t.Kind()) && !cv.Convert(v.Type()).Equal(v) {
+		return reflect.Value{}, false
+	}
+	return cv, ok
+}
+
+// compareValues compares two numbers, strings or times of the same kind.
+func compareValues(value, other any) (int, bool) {
+	if t, ok := value.(time.Time); ok {
+		o, ok := other.(time.Time)
+		return t.Compare(o), ok
+	}
+	v, o := reflect.ValueOf(value), reflect.ValueOf(other)
+	switch {
+	case v.CanInt() && o.CanInt():
+		return cmp.Compare(v.Int(), o.Int()), true
+	case v.CanUint() && o.CanUint():
+		return cmp.Compare(v.Uint(), o.Uint()), true
+	case v.CanFloat() && o.CanFloat():
+		return cmp.Compare(v.Float(), o.Float()), true
+	case v.Kind() == reflect.String && o.Kind() == reflect.String:
+		return cmp.Compare(v.String(), o.String()), true
+	}
+	return 0, false
+}
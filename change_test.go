@@ -0,0 +1,217 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type changeStatus string
+
+type changeAudit struct {
+	CreatedAt time.Time
+	CreatedBy *string
+}
+
+type changeOrder struct {
+	changeAudit
+	*changeOwner
+	Status   changeStatus `json:"status"`
+	Quantity int          `json:"quantity"`
+	Archived bool         `json:"archived"`
+	Note     string       `json:"note"`
+}
+
+type changeOwner struct {
+	Owner string `json:"owner"`
+}
+
+var changeStatuses = Transitions(map[changeStatus][]changeStatus{
+	"draft":  {"review"},
+	"review": {"draft", "published"},
+})
+
+func TestValidateChange(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice, bob := "alice", "bob"
+	old := changeOrder{changeAudit: changeAudit{CreatedAt: created, CreatedBy: &alice}, Status: "draft", Quantity: 5}
+
+	tests := []struct {
+		tag    string
+		update func(o *changeOrder)
+		err    string
+	}{
+		{"t1", func(o *changeOrder) {}, ""},
+		{"t2", func(o *changeOrder) { o.Status = "review"; o.Quantity = 3; o.Archived = true }, ""},
+		{"t3", func(o *changeOrder) { o.Status = "published" }, "status: cannot change from draft to published."},
+		{"t4", func(o *changeOrder) { o.Quantity = 6 }, "quantity: cannot be increased."},
+		{"t5", func(o *changeOrder) { o.CreatedAt = created.Add(time.Second) }, "CreatedAt: cannot be changed."},
+		{"t6", func(o *changeOrder) { o.CreatedAt = created.In(time.FixedZone("X", 3600)) }, ""},
+		{"t7", func(o *changeOrder) { o.CreatedBy = &bob }, "CreatedBy: cannot be changed."},
+		{"t8", func(o *changeOrder) { s := "alice"; o.CreatedBy = &s }, ""},
+		{"t9", func(o *changeOrder) { o.changeOwner = &changeOwner{Owner: "bob"} }, ""},
+	}
+	for _, test := range tests {
+		o := old
+		test.update(&o)
+		err := ValidateChange(&old, &o,
+			Change(&o.Status, changeStatuses),
+			Change(&o.Quantity, Decreasing),
+			Change(&o.CreatedAt, Immutable),
+			Change(&o.CreatedBy, Immutable),
+			Change(&o.Archived, OneWayFrom(false)),
+		)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, test.err, test.tag)
+		}
+	}
+
+	// fields of embedded struct pointers
+	o := old
+	o.changeOwner = &changeOwner{Owner: "bob"}
+	assert.NoError(t, ValidateChange(&old, &o, Change(&o.Owner, OneWayFrom(""))))
+	newer := o
+	newer.changeOwner = &changeOwner{Owner: "carol"}
+	assert.EqualError(t, ValidateChange(&o, &newer, Change(&newer.Owner, OneWayFrom(""))), "owner: cannot be changed from bob.")
+
+	// several errors
+	o = old
+	o.Status, o.Quantity = "published", 10
+	err := ValidateChange(&old, &o, Change(&o.Status, changeStatuses), Change(&o.Quantity, Decreasing))
+	assert.EqualError(t, err, "quantity: cannot be increased; status: cannot change from draft to published.")
+
+	// nil values
+	assert.NoError(t, ValidateChange(nil, &o, Change(&o.Status, Immutable)))
+	assert.NoError(t, ValidateChange(&old, nil, Change(&o.Status, Immutable)))
+
+	// internal errors
+	n := 1
+	err = ValidateChange(&n, &n)
+	assert.Equal(t, ErrStructPointer, err.(InternalError).InternalError())
+	err = ValidateChange(&old, &o, Change(o.Status, Immutable))
+	assert.Equal(t, ErrFieldPointer(0), err.(InternalError).InternalError())
+	err = ValidateChange(&old, &o, Change(&old.Status, Immutable))
+	assert.Equal(t, ErrFieldNotFound(0), err.(InternalError).InternalError())
+	err = ValidateChange(&old, &o, Change(&o.Quantity, changeStatuses))
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+
+	// translation
+	ctx := WithLocale(context.Background(), "fr")
+	err = ValidateChangeWithContext(ctx, &old, &o, Change(&o.Quantity, Decreasing))
+	assert.EqualError(t, err, "quantity: ne peut pas être augmenté.")
+}
+
+func TestOneWayFrom(t *testing.T) {
+	r := OneWayFrom("")
+	assert.NoError(t, r.ValidateTransition("", "a"))
+	assert.NoError(t, r.ValidateTransition("a", "a"))
+	assert.EqualError(t, r.ValidateTransition("a", "b"), "cannot be changed from a")
+	assert.EqualError(t, r.ValidateTransition("a", ""), "cannot be changed from a")
+
+	// values converted to the type of the field
+	assert.NoError(t, OneWayFrom("draft").ValidateTransition(changeStatus("draft"), changeStatus("review")))
+	assert.NoError(t, OneWayFrom(0).ValidateTransition(int64(0), int64(3)))
+	assert.NoError(t, OneWayFrom(2.0).ValidateTransition(2, 3))
+	// values that would lose precision or overflow are not equal to the field
+	assert.EqualError(t, OneWayFrom(0.9).ValidateTransition(0, 1), "cannot be changed from 0")
+	assert.EqualError(t, OneWayFrom(300).ValidateTransition(uint8(44), uint8(45)), "cannot be changed from 44")
+	assert.EqualError(t, OneWayFrom(0.1).ValidateTransition(float32(0.1), float32(1)), "cannot be changed from 0.1")
+	assert.NoError(t, Immutable.ValidateTransition(int8(-1), int8(-1)))
+
+	// nil pointers
+	s := "a"
+	assert.NoError(t, OneWayFrom(nil).ValidateTransition((*string)(nil), &s))
+	assert.EqualError(t, OneWayFrom(nil).ValidateTransition(&s, (*string)(nil)), "cannot be changed from a")
+
+	assert.EqualError(t, r.Error("is frozen").ValidateTransition("a", "b"), "is frozen")
+	assert.EqualError(t, r.ErrorObject(NewError("code", "frozen at {{.value}}")).ValidateTransition("a", "b"), "frozen at a")
+}
+
+func TestTransitions(t *testing.T) {
+	r := Transitions(map[string][]string{"draft": {"review"}})
+	assert.NoError(t, r.ValidateTransition("draft", "review"))
+	assert.NoError(t, r.ValidateTransition("review", "review"))
+	assert.EqualError(t, r.ValidateTransition("review", "draft"), "cannot change from review to draft")
+	assert.NoError(t, r.ValidateTransition(changeStatus("draft"), changeStatus("review")))
+
+	s := "draft"
+	assert.NoError(t, r.ValidateTransition((*string)(nil), &s))
+	assert.NoError(t, r.ValidateTransition(&s, &s))
+
+	err := r.ValidateTransition(1, 2)
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+	// the states are only converted if they keep their values
+	err = Transitions(map[int][]int{0: {1}}).ValidateTransition(0.5, 1.0)
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+	assert.NoError(t, Transitions(map[int][]int{0: {1}}).ValidateTransition(0.0, 1.0))
+
+	assert.EqualError(t, r.Error("bad transition").ValidateTransition("x", "y"), "bad transition")
+	err = r.ErrorObject(NewError("code", "{{.from}} -> {{.to}}")).ValidateTransition("x", "y")
+	assert.EqualError(t, err, "x -> y")
+}
+
+func TestMonotonic(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		tag      string
+		rule     MonotonicRule
+		old, new any
+		err      string
+	}{
+		{"t1", Increasing, 1, 2, ""},
+		{"t2", Increasing, 2, 2, ""},
+		{"t3", Increasing, 2, 1, "cannot be decreased"},
+		{"t4", Decreasing, uint(2), uint(1), ""},
+		{"t5", Decreasing, 1.5, 2.5, "cannot be increased"},
+		{"t6", Increasing, "a", "b", ""},
+		{"t7", Increasing, now, now.Add(-time.Second), "cannot be decreased"},
+		{"t8", Increasing, nil, 1, ""},
+		{"t9", Decreasing, changeStatus("b"), changeStatus("a"), ""},
+	}
+	for _, test := range tests {
+		err := test.rule.ValidateTransition(test.old, test.new)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, test.err, test.tag)
+		}
+	}
+
+	err := Increasing.ValidateTransition(1, "a")
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+	err = Increasing.ValidateTransition(true, false)
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+
+	assert.EqualError(t, Increasing.Error("only up").ValidateTransition(2, 1), "only up")
+	assert.EqualError(t, Decreasing.ErrorObject(NewError("code", "only down")).ValidateTransition(1, 2), "only down")
+	assert.EqualError(t, Immutable.Error("frozen").ValidateTransition(1, 2), "frozen")
+	assert.EqualError(t, Immutable.ErrorObject(NewError("code", "frozen")).ValidateTransition(1, 2), "frozen")
+}
+
+type userKey struct{}
+
+func TestTransitionFunc(t *testing.T) {
+	r := TransitionFunc(func(ctx context.Context, old, new int) error {
+		if ctx.Value(userKey{}) != "admin" && new > old*2 {
+			return errors.New("cannot be more than doubled")
+		}
+		return nil
+	})
+	assert.NoError(t, r.ValidateTransition(2, 4))
+	assert.EqualError(t, r.ValidateTransition(2, 5), "cannot be more than doubled")
+
+	old, o := changeOrder{Quantity: 2}, changeOrder{Quantity: 5}
+	ctx := context.WithValue(context.Background(), userKey{}, "admin")
+	assert.NoError(t, ValidateChangeWithContext(ctx, &old, &o, Change(&o.Quantity, r)))
+	assert.EqualError(t, ValidateChange(&old, &o, Change(&o.Quantity, r)), "quantity: cannot be more than doubled.")
+
+	err := r.ValidateTransition("a", 1)
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+	err = r.ValidateTransition(1, "a")
+	assert.Equal(t, ErrTransitionType, err.(InternalError).InternalError())
+}
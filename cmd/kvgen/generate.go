@@ -0,0 +1,701 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	kvPath          = "github.com/khatibomar/kv"
+	directivePrefix = "//kvgen:rules="
+	defaultMethod   = "Rules"
+)
+
+var (
+	// reservedNames are the identifiers declared by the generated methods, which the receiver must not shadow.
+	reservedNames = []string{"ctx", "errs", "err", "ie", "es", "name", "value"}
+
+	// transformRules are the kv rules modifying the value they validate. The generated methods validate
+	// the fields by value, so these rules would have no effect.
+	transformRules = []string{
+		"TrimSpace", "Lower", "Upper", "NFC", "CollapseSpace", "Transform", "Clamp",
+		"Default", "DefaultFunc", "DefaultFromContext", "DefaultOf",
+	}
+
+	// referenceRules are the kv rules reading other fields of the struct, which only kv.ValidateStruct provides.
+	// The WhenField methods of kv.Required and kv.Skip are rejected as well.
+	referenceRules = []string{
+		"EqualTo", "NotEqualTo", "GreaterThan", "LessThan", "GreaterThanTime", "LessThanTime",
+		"RequiredWith", "RequiredWithout", "ExcludedWith", "WhenField",
+	}
+)
+
+type (
+	// config holds the options of a generator run.
+	config struct {
+		dir    string
+		output string
+		tag    string
+		types  []string
+	}
+
+	// validator describes the methods generated for a struct type.
+	validator struct {
+		Type     string
+		Method   string
+		Receiver string
+		Recv     string
+		Fields   []field
+	}
+
+	// field describes the validation of a single struct field.
+	field struct {
+		KV        string
+		Func      string
+		Expr      string
+		Name      string
+		Anonymous bool
+		Rules     []string
+		// Elements is the call validating the elements of a map, slice or array field, if any.
+		Elements string
+	}
+
+	// structType is a struct type declared in the package being processed.
+	structType struct {
+		name     string
+		method   string
+		explicit bool
+	}
+
+	// method is a method declared in the package being processed.
+	method struct {
+		decl *ast.FuncDecl
+		file *ast.File
+	}
+
+	generator struct {
+		cfg     config
+		fset    *token.FileSet
+		structs map[string]*ast.StructType
+		types   map[string]*ast.TypeSpec
+		files   map[string]*ast.File
+		methods map[string]map[string]method
+		imports map[string]string
+		kvName  string
+	}
+)
+
+// generate parses the package in cfg.dir and returns the source of the generated file.
+func generate(cfg config) ([]byte, error) {
+	g := &generator{
+		cfg:     cfg,
+		fset:    token.NewFileSet(),
+		structs: map[string]*ast.StructType{},
+		types:   map[string]*ast.TypeSpec{},
+		files:   map[string]*ast.File{},
+		methods: map[string]map[string]method{},
+		imports: map[string]string{},
+	}
+
+	paths, err := filepath.Glob(filepath.Join(cfg.dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	var (
+		pkg   string
+		types []structType
+	)
+	for _, p := range paths {
+		base := filepath.Base(p)
+		if strings.HasSuffix(base, "_test.go") || base == cfg.output {
+			continue
+		}
+		f, err := parser.ParseFile(g.fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == "" {
+			pkg = f.Name.Name
+		}
+		g.files[p] = f
+		types = append(types, g.collect(f)...)
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("no Go files found in %s", cfg.dir)
+	}
+
+	for _, name := range cfg.types {
+		if !slices.ContainsFunc(types, func(t structType) bool { return t.name == name }) {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+	}
+
+	var validators []validator
+	for _, t := range types {
+		requested := slices.Contains(cfg.types, t.name)
+		if len(cfg.types) > 0 && !requested {
+			continue
+		}
+		m, ok := g.methods[t.name][t.method]
+		if !ok {
+			if t.explicit || requested {
+				return nil, fmt.Errorf("%s: method %s not found", t.name, t.method)
+			}
+			continue
+		}
+		v, err := g.validator(t, m)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.name, t.method, err)
+		}
+		validators = append(validators, v)
+	}
+	if len(validators) == 0 {
+		return nil, errors.New("no rule sets found")
+	}
+
+	return g.render(pkg, validators)
+}
+
+// collect records the struct types and methods declared in a file.
+// It returns the struct types in the order of their declaration.
+func (g *generator) collect(f *ast.File) []structType {
+	var types []structType
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				g.types[ts.Name.Name] = ts
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				g.structs[ts.Name.Name] = st
+				t := structType{name: ts.Name.Name, method: defaultMethod}
+				doc := ts.Doc
+				if doc == nil && len(d.Specs) == 1 {
+					doc = d.Doc
+				}
+				if name, ok := directive(doc); ok {
+					t.method, t.explicit = name, true
+				}
+				types = append(types, t)
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) != 1 {
+				continue
+			}
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				if g.methods[id.Name] == nil {
+					g.methods[id.Name] = map[string]method{}
+				}
+				g.methods[id.Name][d.Name.Name] = method{decl: d, file: f}
+			}
+		}
+	}
+	return types
+}
+
+// directive returns the method name given by a kvgen directive in the comment group, if any.
+func directive(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, c := range doc.List {
+		if name, ok := strings.CutPrefix(c.Text, directivePrefix); ok {
+			return strings.TrimSpace(name), true
+		}
+	}
+	return "", false
+}
+
+// validator builds the description of the methods to generate from the method declaring the rule set.
+func (g *generator) validator(t structType, m method) (validator, error) {
+	fd := m.decl
+	kvName, imports := fileImports(m.file)
+	if kvName == "" {
+		return validator{}, fmt.Errorf("%s is not imported", kvPath)
+	}
+	if g.kvName == "" {
+		g.kvName = kvName
+	} else if g.kvName != kvName {
+		return validator{}, fmt.Errorf("%s must be imported with the same name in all files", kvPath)
+	}
+
+	recvField := fd.Recv.List[0]
+	if len(recvField.Names) != 1 || recvField.Names[0].Name == "_" {
+		return validator{}, errors.New("the receiver must be named")
+	}
+	recv := recvField.Names[0].Name
+	if slices.Contains(reservedNames, recv) {
+		return validator{}, fmt.Errorf("the receiver must not be named %s", recv)
+	}
+
+	if fd.Type.Params.NumFields() != 0 || fd.Type.Results.NumFields() != 1 ||
+		g.expr(fd.Type.Results.List[0].Type) != "[]*"+kvName+".FieldRules" {
+		return validator{}, fmt.Errorf("must have the signature func() []*%s.FieldRules", kvName)
+	}
+	if fd.Body == nil || len(fd.Body.List) != 1 {
+		return validator{}, errors.New("must consist of a single return statement")
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return validator{}, errors.New("must consist of a single return statement")
+	}
+	lit, ok := ret.Results[0].(*ast.CompositeLit)
+	if !ok {
+		return validator{}, errors.New("must return a slice literal")
+	}
+
+	v := validator{
+		Type:     t.name,
+		Method:   t.method,
+		Receiver: recv,
+		Recv:     recv + " " + g.expr(recvField.Type),
+	}
+	for i, elt := range lit.Elts {
+		f, err := g.field(t.name, recv, kvName, elt)
+		if err != nil {
+			return validator{}, fmt.Errorf("field #%d: %w", i, err)
+		}
+		for _, r := range f.rules {
+			g.use(r, imports)
+		}
+		v.Fields = append(v.Fields, f.field)
+	}
+	return v, nil
+}
+
+type parsedField struct {
+	field
+	rules []ast.Expr
+}
+
+// field parses a kv.Field call of a rule set, optionally followed by a CollectAll call.
+func (g *generator) field(typeName, recv, kvName string, elt ast.Expr) (parsedField, error) {
+	collectAll := false
+	if call, ok := elt.(*ast.CallExpr); ok && len(call.Args) == 0 {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "CollectAll" {
+			collectAll = true
+			elt = sel.X
+		}
+	}
+	call, ok := elt.(*ast.CallExpr)
+	if !ok || g.expr(call.Fun) != kvName+".Field" || len(call.Args) == 0 {
+		return parsedField{}, fmt.Errorf("must be a call to %s.Field", kvName)
+	}
+	ptr, ok := call.Args[0].(*ast.UnaryExpr)
+	if !ok || ptr.Op != token.AND {
+		return parsedField{}, errors.New("the field must be specified as a pointer")
+	}
+	names, ok := selectors(ptr.X, recv)
+	if !ok {
+		return parsedField{}, fmt.Errorf("the field must be selected from the receiver %s", recv)
+	}
+	sf, err := g.resolve(typeName, names)
+	if err != nil {
+		return parsedField{}, err
+	}
+
+	f := parsedField{
+		field: field{
+			KV:        kvName,
+			Func:      "ValidateValueWithContext[any]",
+			Expr:      g.expr(ptr.X),
+			Name:      g.errorFieldName(sf),
+			Anonymous: sf.anonymous,
+		},
+		rules: call.Args[1:],
+	}
+	switch coll, known := g.collection(sf.typ); {
+	case collectAll:
+		// kv.ValidateAllWithContext validates the elements like kv.ValidateStruct
+		f.Func = "ValidateAllWithContext"
+	case !known:
+		// the kind of the field is unknown, so it is validated like kv.ValidateStruct does, with reflection
+		f.Func = "ValidateWithContext"
+	case coll != nil:
+		f.Elements = g.elements(kvName, f.Expr, coll)
+	}
+	for i, r := range f.rules {
+		if err := g.checkRule(kvName, r); err != nil {
+			return parsedField{}, err
+		}
+		rule := g.expr(r)
+		if call.Ellipsis.IsValid() && i == len(f.rules)-1 {
+			rule += "..."
+		}
+		f.Rules = append(f.Rules, rule)
+	}
+	return f, nil
+}
+
+// checkRule returns an error positioned at the first rule of the expression that the generated methods cannot run.
+func (g *generator) checkRule(kvName string, e ast.Expr) error {
+	var err error
+	ast.Inspect(e, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		id, ok := sel.X.(*ast.Ident)
+		fromKV := ok && id.Name == kvName && id.Obj == nil
+		switch name := sel.Sel.Name; {
+		case fromKV && slices.Contains(transformRules, name):
+			err = fmt.Errorf("%s: %s is not supported, as the fields are validated by value and cannot be modified",
+				g.fset.Position(sel.Pos()), g.expr(sel))
+		case fromKV && slices.Contains(referenceRules, name), name == "WhenField":
+			err = fmt.Errorf("%s: %s is not supported, as the rules referencing other fields require %s.ValidateStruct",
+				g.fset.Position(sel.Pos()), g.expr(sel), kvName)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// collection returns the unnamed map, slice or array type of a field whose elements are validated
+// by kv.ValidateStruct if they implement kv.Validatable, or nil for the other fields. It reports false
+// if the kind of the field cannot be determined from the source, such as for the types declared in other packages.
+func (g *generator) collection(typ ast.Expr) (ast.Expr, bool) {
+	switch t := typ.(type) {
+	case *ast.ParenExpr:
+		return g.collection(t.X)
+	case *ast.ArrayType:
+		if isBasic(t.Elt) {
+			return nil, true
+		}
+		return t, true
+	case *ast.MapType:
+		if isBasic(t.Value) {
+			return nil, true
+		}
+		return t, true
+	case *ast.StarExpr:
+		// kv.ValidateStruct validates the elements of a collection through a pointer as well
+		if coll, known := g.collection(t.X); coll != nil || !known {
+			return nil, false
+		}
+		return nil, true
+	case *ast.Ident:
+		if isBasic(t) {
+			return nil, true
+		}
+		ts, ok := g.types[t.Name]
+		if !ok || ts.TypeParams != nil {
+			return nil, false
+		}
+		if g.validatable(t.Name) {
+			// the value validates itself instead of its elements
+			return nil, true
+		}
+		return g.collection(ts.Type)
+	case *ast.StructType, *ast.FuncType, *ast.ChanType:
+		return nil, true
+	}
+	return nil, false
+}
+
+// validatable reports whether values of a named type declare a Validate or ValidateWithContext method.
+func (g *generator) validatable(name string) bool {
+	for _, m := range []string{"Validate", "ValidateWithContext"} {
+		if md, ok := g.methods[name][m]; ok {
+			if _, ptr := md.decl.Recv.List[0].Type.(*ast.StarExpr); !ptr {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// elements returns the call validating the elements of a map, slice or array field with EachEntry or EachOf.
+func (g *generator) elements(kvName, expr string, coll ast.Expr) string {
+	g.use(coll, g.importsAt(coll))
+	switch t := coll.(type) {
+	case *ast.MapType:
+		key, value := g.expr(t.Key), g.expr(t.Value)
+		return fmt.Sprintf("%s.ValidateValueWithContext[map[%s]%s](ctx, %s, %s.EachEntry[%s, %s](nil, nil))", kvName, key, value, expr, kvName, key, value)
+	case *ast.ArrayType:
+		elem := g.expr(t.Elt)
+		if t.Len != nil {
+			expr += "[:]"
+		}
+		return fmt.Sprintf("%s.ValidateValueWithContext[[]%s](ctx, %s, %s.EachOf[%s]())", kvName, elem, expr, kvName, elem)
+	}
+	return ""
+}
+
+// importsAt returns the paths of the imports by name of the file declaring a node.
+func (g *generator) importsAt(n ast.Node) map[string]string {
+	_, imports := fileImports(g.files[g.fset.File(n.Pos()).Name()])
+	return imports
+}
+
+// isBasic reports whether a type is a predeclared type other than any, whose values cannot implement kv.Validatable.
+func isBasic(typ ast.Expr) bool {
+	id, ok := typ.(*ast.Ident)
+	if !ok || id.Obj != nil {
+		return false
+	}
+	switch id.Name {
+	case "bool", "string", "error", "byte", "rune", "uintptr",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
+}
+
+// selectors returns the names of the fields selected from the receiver by the expression.
+func selectors(e ast.Expr, recv string) ([]string, bool) {
+	switch x := e.(type) {
+	case *ast.ParenExpr:
+		return selectors(x.X, recv)
+	case *ast.SelectorExpr:
+		names, ok := selectors(x.X, recv)
+		return append(names, x.Sel.Name), ok
+	case *ast.Ident:
+		return nil, x.Name == recv
+	}
+	return nil, false
+}
+
+// structField is a resolved struct field.
+type structField struct {
+	name      string
+	anonymous bool
+	tag       *ast.BasicLit
+	typ       ast.Expr
+}
+
+// resolve finds the field selected by the given names, starting from the named struct type.
+func (g *generator) resolve(typeName string, names []string) (structField, error) {
+	if len(names) == 0 {
+		return structField{}, errors.New("the field must be specified as a pointer")
+	}
+	st := g.structs[typeName]
+	var sf structField
+	for i, name := range names {
+		var ok bool
+		if sf, ok = g.lookup(st, name); !ok {
+			return structField{}, fmt.Errorf("cannot resolve field %s", strings.Join(names[:i+1], "."))
+		}
+		if i < len(names)-1 {
+			if st, ok = g.structs[typeIdent(sf.typ)]; !ok {
+				return structField{}, fmt.Errorf("cannot resolve field %s", strings.Join(names[:i+2], "."))
+			}
+		}
+	}
+	return sf, nil
+}
+
+// lookup finds a field of a struct by name, including the fields promoted from embedded structs
+// declared in the same package. Like the Go selector rules, shallower fields take precedence.
+func (g *generator) lookup(st *ast.StructType, name string) (structField, bool) {
+	level := []*ast.StructType{st}
+	for len(level) > 0 {
+		var next []*ast.StructType
+		for _, s := range level {
+			for _, f := range s.Fields.List {
+				if len(f.Names) == 0 {
+					embedded := typeIdent(f.Type)
+					if embedded == name {
+						return structField{name: name, anonymous: true, tag: f.Tag, typ: f.Type}, true
+					}
+					if es, ok := g.structs[embedded]; ok {
+						next = append(next, es)
+					}
+					continue
+				}
+				for _, n := range f.Names {
+					if n.Name == name {
+						return structField{name: name, tag: f.Tag, typ: f.Type}, true
+					}
+				}
+			}
+		}
+		level = next
+	}
+	return structField{}, false
+}
+
+// typeIdent returns the name of a possibly qualified or pointer type.
+func typeIdent(e ast.Expr) string {
+	switch x := e.(type) {
+	case *ast.StarExpr:
+		return typeIdent(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.Ident:
+		return x.Name
+	}
+	return ""
+}
+
+// errorFieldName returns the name used to represent the validation error of a struct field.
+// It follows the same rules as kv.ValidateStruct.
+func (g *generator) errorFieldName(f structField) string {
+	if f.tag != nil {
+		if tags, err := strconv.Unquote(f.tag.Value); err == nil {
+			if tag := reflect.StructTag(tags).Get(g.cfg.tag); tag != "" && tag != "-" {
+				if cps := strings.SplitN(tag, ",", 2); cps[0] != "" {
+					return cps[0]
+				}
+			}
+		}
+	}
+	return f.name
+}
+
+// fileImports returns the name under which kv is imported by a file, and the paths of all imports by name.
+func fileImports(f *ast.File) (string, map[string]string) {
+	var kvName string
+	imports := map[string]string{}
+	for _, spec := range f.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if p == kvPath {
+			kvName = name
+		}
+		imports[name] = p
+	}
+	return kvName, imports
+}
+
+// use records the imports referenced by an expression.
+func (g *generator) use(e ast.Expr, imports map[string]string) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				if p, ok := imports[id.Name]; ok {
+					g.imports[id.Name] = p
+				}
+			}
+		}
+		return true
+	})
+}
+
+// expr returns the source code of an expression.
+func (g *generator) expr(e ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, g.fset, e)
+	return buf.String()
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by kvgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{range .Imports}}	{{.}}
+{{end}})
+{{range .Validators}}
+// Validate validates the fields of {{.Type}} using the rules declared by {{.Method}}.
+func ({{.Recv}}) Validate() error {
+	return {{.Receiver}}.ValidateWithContext(context.TODO())
+}
+
+// ValidateWithContext validates the fields of {{.Type}} with the given context using the rules declared by {{.Method}}.
+func ({{.Recv}}) ValidateWithContext(ctx context.Context) error {
+	errs := {{$.KV}}.Errors{}
+{{range .Fields}}	if err := {{.KV}}.{{.Func}}(ctx, {{.Expr}}{{range .Rules}}, {{.}}{{end}}); err != nil {
+{{template "record" .}}
+	}{{if .Elements}} else if err := {{.Elements}}; err != nil {
+{{template "record" .}}
+	}{{end}}
+{{end}}	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+{{end}}
+{{- define "record"}}		if ie, ok := err.({{.KV}}.InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+{{- if .Anonymous}}
+		if es, ok := err.({{.KV}}.Errors); ok {
+			for name, value := range es {
+				errs[name] = value
+			}
+		} else {
+			errs[{{printf "%q" .Name}}] = err
+		}
+{{- else}}
+		errs[{{printf "%q" .Name}}] = err
+{{- end}}
+{{- end}}`))
+
+// render executes the file template and formats the result.
+func (g *generator) render(pkg string, validators []validator) ([]byte, error) {
+	g.imports["context"] = "context"
+	g.imports[g.kvName] = kvPath
+
+	// standard library imports first, separated by a blank line from the others
+	var std, others []string
+	for name, p := range g.imports {
+		spec := strconv.Quote(p)
+		if name != path.Base(p) {
+			spec = name + " " + spec
+		}
+		if first, _, _ := strings.Cut(p, "/"); strings.Contains(first, ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	byPath := func(a, b string) int {
+		return strings.Compare(importPath(a), importPath(b))
+	}
+	slices.SortFunc(std, byPath)
+	slices.SortFunc(others, byPath)
+	imports := std
+	if len(others) > 0 {
+		imports = append(append(imports, ""), others...)
+	}
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, map[string]any{
+		"Package":    pkg,
+		"Imports":    imports,
+		"Validators": validators,
+		"KV":         g.kvName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// importPath returns the quoted path of an import spec.
+func importPath(spec string) string {
+	return spec[strings.IndexByte(spec, '"'):]
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		tag    string
+		dir    string
+		types  []string
+		golden string
+	}{
+		{"t1", "basic", nil, "basic.golden"},
+		{"t2", "basic", []string{"Address"}, "basic_address.golden"},
+		{"t3", "alias", nil, "alias.golden"},
+		{"t4", "elements", nil, "elements.golden"},
+	}
+	for _, test := range tests {
+		cfg := config{
+			dir:    filepath.Join("testdata", test.dir),
+			output: "kv_validate.go",
+			tag:    "json",
+			types:  test.types,
+		}
+		src, err := generate(cfg)
+		if !assert.NoError(t, err, test.tag) {
+			continue
+		}
+
+		golden := filepath.Join("testdata", test.golden)
+		if *update {
+			if err := os.WriteFile(golden, src, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := os.ReadFile(golden)
+		if assert.NoError(t, err, test.tag) {
+			assert.Equal(t, string(want), string(src), test.tag)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		tag   string
+		dir   string
+		types []string
+		err   string
+	}{
+		{"t1", "errors/body", nil, "Model.Rules: must consist of a single return statement"},
+		{"t2", "errors/field", nil, "Model.Rules: field #0: the field must be selected from the receiver m"},
+		{"t3", "errors/directive", nil, "Model: method Missing not found"},
+		{"t4", "basic", []string{"Unknown"}, "struct type Unknown not found"},
+		{"t5", "basic", []string{"NoRules"}, "NoRules: method Rules not found"},
+		{"t6", "missing", nil, "no Go files found in testdata/missing"},
+		{"t7", "errors/transform", nil, "Model.Rules: field #1: testdata/errors/transform/model.go:13:43: kv.Default is not supported, as the fields are validated by value and cannot be modified"},
+		{"t8", "errors/reference", []string{"Model"}, "Model.Rules: field #1: testdata/errors/reference/model.go:13:24: kv.EqualTo is not supported, as the rules referencing other fields require kv.ValidateStruct"},
+		{"t9", "errors/reference", []string{"Contact"}, "Contact.Rules: field #0: testdata/errors/reference/model.go:24:22: kv.Required.WhenField is not supported, as the rules referencing other fields require kv.ValidateStruct"},
+	}
+	for _, test := range tests {
+		cfg := config{
+			dir:    filepath.Join("testdata", test.dir),
+			output: "kv_validate.go",
+			tag:    "json",
+			types:  test.types,
+		}
+		_, err := generate(cfg)
+		assert.EqualError(t, err, test.err, test.tag)
+	}
+}
@@ -0,0 +1,81 @@
+// Command kvgen generates Validate and ValidateWithContext methods for struct types from the
+// rule sets declared by their Rules methods, so that the structs can be validated without
+// the reflection performed by kv.ValidateStruct.
+//
+// A rule set is declared by a method that returns the field rules of the struct,
+// the same way they would be passed to kv.ValidateStruct:
+//
+//	func (c *Customer) Rules() []*kv.FieldRules {
+//	    return []*kv.FieldRules{
+//	        kv.Field(&c.Name, kv.Required, kv.Length(5, 20)),
+//	        kv.Field(&c.Email, kv.Required, is.Email),
+//	    }
+//	}
+//
+// The method must consist of a single return statement. A different method name may be chosen
+// for a type by annotating its declaration with a directive:
+//
+//	//kvgen:rules=ValidationRules
+//	type Customer struct { ... }
+//
+// The generated methods run the rules of each field in order with kv.ValidateValueWithContext,
+// and report the errors in a kv.Errors keyed by the same names kv.ValidateStruct would use.
+// The fields whose rules are followed by CollectAll() are validated with kv.ValidateAllWithContext.
+// Like kv.ValidateStruct, the elements of map, slice and array fields are validated with kv.EachOf or
+// kv.EachEntry if they implement kv.Validatable. The fields whose type is declared in another package
+// or is an interface are validated with kv.ValidateWithContext, as their kind is only known at run time.
+// The rules that the generated methods cannot run are rejected with an error giving their position:
+// the transform rules, such as kv.TrimSpace and kv.Default, because the fields are validated by value,
+// and the rules referencing other fields, such as kv.EqualTo and kv.WhenField, because they require kv.ValidateStruct.
+//
+// Usage:
+//
+//	//go:generate kvgen
+//
+//	kvgen [flags]
+//
+// The flags are:
+//
+//	-dir string
+//	    the directory of the package to process (default ".")
+//	-output string
+//	    the name of the generated file, relative to dir (default "kv_validate.go")
+//	-tag string
+//	    the struct tag used to name the fields in the errors (default "json")
+//	-type string
+//	    a comma-separated list of type names to process; all types with a rule set by default
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		cfg   config
+		types string
+	)
+	flag.StringVar(&cfg.dir, "dir", ".", "the directory of the package to process")
+	flag.StringVar(&cfg.output, "output", "kv_validate.go", "the name of the generated file, relative to dir")
+	flag.StringVar(&cfg.tag, "tag", "json", "the struct tag used to name the fields in the errors")
+	flag.StringVar(&types, "type", "", "a comma-separated list of type names to process")
+	flag.Parse()
+
+	if types != "" {
+		cfg.types = strings.Split(types, ",")
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kvgen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.dir, cfg.output), src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "kvgen: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,31 @@
+package alias
+
+import (
+	"context"
+	"errors"
+
+	v "github.com/khatibomar/kv"
+)
+
+type User struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Tags     []string
+}
+
+func (u *User) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&u.Name, nameRules...),
+		v.Field(&u.Password, v.Required, v.WithContext(checkPassword)),
+		v.Field(&u.Tags, v.Each(v.Required)),
+	}
+}
+
+var nameRules = []v.Rule[any]{v.Required, v.Length(2, 20)}
+
+func checkPassword(ctx context.Context, value any) error {
+	if value == "secret" {
+		return errors.New("too weak")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"regexp"
+
+	"github.com/khatibomar/kv"
+	"github.com/khatibomar/kv/is"
+)
+
+type Address struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip,omitempty"`
+}
+
+func (a Address) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&a.Street, kv.Required, kv.Length(5, 50)),
+		kv.Field(&a.Zip, kv.Required, kv.Match(regexp.MustCompile("^[0-9]{5}$"))),
+	}
+}
+
+type Base struct {
+	ID int `json:"id"`
+}
+
+func (b Base) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&b.ID, kv.Required),
+	}
+}
+
+//kvgen:rules=ValidationRules
+type Customer struct {
+	Base
+	Name    string
+	Email   string  `json:"email"`
+	Address Address `json:"address"`
+	Ignored string  `json:"-"`
+}
+
+func (c *Customer) ValidationRules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&c.Base),
+		kv.Field(&c.ID, kv.Any(kv.Max(1000))),
+		kv.Field(&c.Name, kv.Required, kv.When(c.Email == "", kv.Length(5, 20))),
+		kv.Field(&c.Email, is.Email, kv.Length(0, 100)).CollectAll(),
+		kv.Field(&c.Address),
+		kv.Field(&c.Ignored, kv.Required),
+	}
+}
+
+type NoRules struct{ A string }
@@ -0,0 +1,51 @@
+package elements
+
+import (
+	"errors"
+	"time"
+
+	"github.com/khatibomar/kv"
+)
+
+type Item struct {
+	SKU string `json:"sku"`
+}
+
+func (i Item) Validate() error {
+	if i.SKU == "" {
+		return errors.New("sku is required")
+	}
+	return nil
+}
+
+type Items []Item
+
+type Codes []string
+
+func (c Codes) Validate() error {
+	return nil
+}
+
+type Order struct {
+	Items    []Item           `json:"items"`
+	Pair     [2]Item          `json:"pair"`
+	ByName   map[string]*Item `json:"by_name"`
+	Named    Items            `json:"named"`
+	Codes    Codes            `json:"codes"`
+	Tags     []string         `json:"tags"`
+	Created  time.Time        `json:"created"`
+	Metadata any              `json:"metadata"`
+}
+
+func (o *Order) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&o.Items, kv.Required),
+		kv.Field(&o.Pair),
+		kv.Field(&o.ByName),
+		kv.Field(&o.Named),
+		kv.Field(&o.Codes),
+		kv.Field(&o.Tags, kv.Each(kv.Required)),
+		kv.Field(&o.Created, kv.Required),
+		kv.Field(&o.Metadata),
+	}
+}
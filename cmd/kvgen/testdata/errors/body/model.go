@@ -0,0 +1,12 @@
+package body
+
+import "github.com/khatibomar/kv"
+
+type Model struct {
+	A string
+}
+
+func (m Model) Rules() []*kv.FieldRules {
+	rules := []*kv.FieldRules{kv.Field(&m.A, kv.Required)}
+	return rules
+}
@@ -0,0 +1,6 @@
+package directive
+
+//kvgen:rules=Missing
+type Model struct {
+	A string
+}
@@ -0,0 +1,13 @@
+package field
+
+import "github.com/khatibomar/kv"
+
+type Model struct {
+	A string
+}
+
+var other Model
+
+func (m Model) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{kv.Field(&other.A, kv.Required)}
+}
@@ -0,0 +1,26 @@
+package reference
+
+import "github.com/khatibomar/kv"
+
+type Model struct {
+	Password string
+	Confirm  string
+}
+
+func (m *Model) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&m.Password, kv.Required),
+		kv.Field(&m.Confirm, kv.EqualTo(&m.Password)),
+	}
+}
+
+type Contact struct {
+	Phone string
+	Email string
+}
+
+func (c *Contact) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&c.Email, kv.Required.WhenField(&c.Phone, func(v any) bool { return v == "" })),
+	}
+}
@@ -0,0 +1,15 @@
+package transform
+
+import "github.com/khatibomar/kv"
+
+type Model struct {
+	Name string
+	Role string
+}
+
+func (m *Model) Rules() []*kv.FieldRules {
+	return []*kv.FieldRules{
+		kv.Field(&m.Name, kv.Required),
+		kv.Field(&m.Role, kv.When(m.Name != "", kv.Default("user")), kv.Required),
+	}
+}
@@ -0,0 +1,300 @@
+package kv
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+var (
+	// ErrUnique is the error that returns when a collection contains duplicate items.
+	ErrUnique = NewError("validation_unique", "must not contain duplicate items")
+	// ErrMinItems is the error that returns when a collection contains too few items.
+	ErrMinItems = NewError("validation_min_items", "must contain at least {{.min}} items")
+	// ErrMaxItems is the error that returns when a collection contains too many items.
+	ErrMaxItems = NewError("validation_max_items", "must contain no more than {{.max}} items")
+	// ErrContains is the error that returns when a collection does not contain a required item.
+	ErrContains = NewError("validation_contains", "must contain {{.value}}")
+	// ErrItemsIn is the error that returns when a collection contains an item that is not allowed.
+	ErrItemsIn = NewError("validation_items_in", "must contain only valid items")
+	// ErrSortedAscending is the error that returns when a collection is not sorted in ascending order.
+	ErrSortedAscending = NewError("validation_sorted_ascending", "must be sorted in ascending order")
+	// ErrSortedDescending is the error that returns when a collection is not sorted in descending order.
+	ErrSortedDescending = NewError("validation_sorted_descending", "must be sorted in descending order")
+)
+
+type (
+	// UniqueRule is a validation rule that checks if the items of a slice are unique.
+	UniqueRule[E any] struct {
+		// duplicate returns the index of the first duplicate item, or -1 if there is none.
+		// It returns an InternalError if the key of an item is not comparable.
+		duplicate func(value []E) (int, error)
+		err       Error
+	}
+
+	// ItemsRule is a validation rule that checks if the number of items of a slice is within a bound.
+	ItemsRule[E any] struct {
+		n   int
+		max bool
+		err Error
+	}
+
+	// ContainsRule is a validation rule that checks if a slice contains a value.
+	ContainsRule[E comparable] struct {
+		value E
+		err   Error
+	}
+
+	// ItemsInRule is a validation rule that checks if every item of a slice can be found in a list of values.
+	ItemsInRule[E comparable] struct {
+		values []E
+		err    Error
+	}
+
+	// SortedRule is a validation rule that checks if the items of a slice are sorted.
+	SortedRule[E any] struct {
+		compare func(a, b E) int
+		desc    bool
+		// err is the custom error, if any, kept when the order is changed by Desc
+		err Error
+	}
+)
+
+// Unique returns a validation rule that checks if the items of a slice are unique.
+// The index of the first duplicate item is given as the "index" param of the error.
+// An empty slice is considered valid.
+func Unique[E comparable]() UniqueRule[E] {
+	return UniqueBy(func(e E) E { return e })
+}
+
+// UniqueBy returns a validation rule that checks if the keys extracted from the items of a slice are unique.
+// It can be used to check that no two items have the same field value. For example,
+//
+//	kv.UniqueBy(func(u User) string { return u.Email })
+//
+// Please refer to Unique for the details. If K is an interface type, or holds one, and a key is not comparable,
+// such as a slice held by an interface, an InternalError is returned.
+func UniqueBy[E any, K comparable](key func(E) K) UniqueRule[E] {
+	checkKeys := mayBeUncomparable(reflect.TypeFor[K]())
+	duplicate := func(value []E) (int, error) {
+		seen := make(map[K]struct{}, len(value))
+		for i, e := range value {
+			k := key(e)
+			if checkKeys && !isComparable(k) {
+				return -1, NewInternalError(fmt.Errorf("the key of the item %d is not comparable", i))
+			}
+			if _, ok := seen[k]; ok {
+				return i, nil
+			}
+			seen[k] = struct{}{}
+		}
+		return -1, nil
+	}
+	return UniqueRule[E]{duplicate: duplicate, err: ErrUnique}
+}
+
+// Validate checks if the given value is valid or not.
+func (r UniqueRule[E]) Validate(value []E) error {
+	i, err := r.duplicate(value)
+	if err != nil {
+		return err
+	}
+	if i >= 0 {
+		return withParam(r.err, "index", i)
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r UniqueRule[E]) Error(message string) UniqueRule[E] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r UniqueRule[E]) ErrorObject(err Error) UniqueRule[E] {
+	r.err = err
+	return r
+}
+
+// MinItems returns a validation rule that checks if a slice contains at least min items.
+// Unlike Length, an empty slice is considered invalid when min is greater than 0.
+func MinItems[E any](min int) ItemsRule[E] {
+	return ItemsRule[E]{n: min, err: ErrMinItems.SetParams(map[string]any{"min": min})}
+}
+
+// MaxItems returns a validation rule that checks if a slice contains no more than max items.
+// The index of the first extra item is given as the "index" param of the error.
+func MaxItems[E any](max int) ItemsRule[E] {
+	return ItemsRule[E]{n: max, max: true, err: ErrMaxItems.SetParams(map[string]any{"max": max})}
+}
+
+// Validate checks if the given value is valid or not.
+func (r ItemsRule[E]) Validate(value []E) error {
+	if r.max && len(value) > r.n {
+		return withParam(r.err, "index", r.n)
+	}
+	if !r.max && len(value) < r.n {
+		return r.err
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r ItemsRule[E]) Error(message string) ItemsRule[E] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ItemsRule[E]) ErrorObject(err Error) ItemsRule[E] {
+	r.err = err
+	return r
+}
+
+// Contains returns a validation rule that checks if a slice contains the given value.
+// The value is given as the "value" param of the error. An empty slice is considered valid.
+// Use MinItems to make sure a slice is not empty.
+func Contains[E comparable](value E) ContainsRule[E] {
+	return ContainsRule[E]{value: value, err: ErrContains.SetParams(map[string]any{"value": value})}
+}
+
+// Validate checks if the given value is valid or not.
+func (r ContainsRule[E]) Validate(value []E) error {
+	if len(value) == 0 || slices.Contains(value, r.value) {
+		return nil
+	}
+	return r.err
+}
+
+// Error sets the error message for the rule.
+func (r ContainsRule[E]) Error(message string) ContainsRule[E] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ContainsRule[E]) ErrorObject(err Error) ContainsRule[E] {
+	r.err = err
+	return r
+}
+
+// ItemsIn returns a validation rule that checks if every item of a slice can be found in the given list of values.
+// The index of the first invalid item is given as the "index" param of the error.
+// An empty slice is considered valid.
+func ItemsIn[E comparable](values ...E) ItemsInRule[E] {
+	return ItemsInRule[E]{values: values, err: ErrItemsIn}
+}
+
+// Validate checks if the given value is valid or not.
+func (r ItemsInRule[E]) Validate(value []E) error {
+	for i, e := range value {
+		if !slices.Contains(r.values, e) {
+			return withParam(r.err, "index", i)
+		}
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r ItemsInRule[E]) Error(message string) ItemsInRule[E] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ItemsInRule[E]) ErrorObject(err Error) ItemsInRule[E] {
+	r.err = err
+	return r
+}
+
+// Sorted returns a validation rule that checks if the items of a slice are sorted in ascending order.
+// By calling Desc, the rule will check if the items are sorted in descending order.
+// Equal adjacent items are allowed. The index of the first item out of order is given
+// as the "index" param of the error. An empty slice is considered valid.
+func Sorted[E Ordered]() SortedRule[E] {
+	return SortedFunc(cmp.Compare[E])
+}
+
+// SortedFunc returns a validation rule that checks if the items of a slice are sorted in ascending order
+// as determined by the compare function. Please refer to Sorted for the details.
+func SortedFunc[E any](compare func(a, b E) int) SortedRule[E] {
+	return SortedRule[E]{compare: compare}
+}
+
+// Desc sets the rule to check if the items are sorted in descending order.
+// The error is ErrSortedDescending, unless a custom error or message was set by ErrorObject or Error.
+func (r SortedRule[E]) Desc() SortedRule[E] {
+	r.desc = true
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r SortedRule[E]) Validate(value []E) error {
+	for i := 1; i < len(value); i++ {
+		c := r.compare(value[i-1], value[i])
+		if r.desc && c < 0 || !r.desc && c > 0 {
+			return withParam(r.getError(), "index", i)
+		}
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r SortedRule[E]) Error(message string) SortedRule[E] {
+	r.err = r.getError().SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r SortedRule[E]) ErrorObject(err Error) SortedRule[E] {
+	r.err = err
+	return r
+}
+
+// getError returns the custom error if set, otherwise returns the default error of the order.
+func (r SortedRule[E]) getError() Error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.desc {
+		return ErrSortedDescending
+	}
+	return ErrSortedAscending
+}
+
+// isComparable checks if a value can be compared without panicking.
+func isComparable(v any) bool {
+	return v == nil || reflect.ValueOf(v).Comparable()
+}
+
+// mayBeUncomparable checks if the values of a comparable type may panic when compared,
+// which is the case of interface types and of the structs and arrays holding them.
+func mayBeUncomparable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return mayBeUncomparable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if mayBeUncomparable(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withParam returns a copy of the error with the given param added.
+// Unlike AddParam, the params of the original error are not modified.
+func withParam(err Error, name string, value any) Error {
+	params := maps.Clone(err.Params())
+	if params == nil {
+		params = make(map[string]any, 1)
+	}
+	params[name] = value
+	return err.SetParams(params)
+}
@@ -0,0 +1,199 @@
+package kv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value []string
+		err   string
+		index any
+	}{
+		{"t1", nil, "", nil},
+		{"t2", []string{"a", "b", "c"}, "", nil},
+		{"t3", []string{"a", "b", "a", "b"}, "must not contain duplicate items", 2},
+		{"t4", []string{"a", "a"}, "must not contain duplicate items", 1},
+	}
+	for _, test := range tests {
+		err := Unique[string]().Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		assertIndex(t, test.index, err, test.tag)
+	}
+
+	err := Unique[int]().Error("no repeats").Validate([]int{1, 1})
+	assert.EqualError(t, err, "no repeats")
+	err = Unique[int]().ErrorObject(NewError("code", "abc")).Validate([]int{1, 1})
+	assert.Equal(t, "code", err.(Error).Code())
+
+	// the items are not boxed, so the allocations do not depend on their number
+	items := make([]int, 200)
+	for i := range items {
+		items[i] = i * 1000
+	}
+	rule := Unique[int]()
+	few := testing.AllocsPerRun(100, func() { _ = rule.Validate(items[:20]) })
+	many := testing.AllocsPerRun(100, func() { _ = rule.Validate(items) })
+	assert.Equal(t, few, many)
+}
+
+func TestUniqueBy(t *testing.T) {
+	type user struct {
+		Name, Email string
+	}
+	rule := UniqueBy(func(u user) string { return strings.ToLower(u.Email) })
+	assert.NoError(t, rule.Validate([]user{{"a", "a@x.com"}, {"b", "b@x.com"}}))
+	err := rule.Validate([]user{{"a", "a@x.com"}, {"b", "b@x.com"}, {"c", "A@x.com"}})
+	assert.EqualError(t, err, "must not contain duplicate items")
+	assertIndex(t, 2, err, "index")
+
+	// the shared error is not modified
+	assert.Nil(t, ErrUnique.Params())
+
+	// keys of interface types
+	byValue := UniqueBy(func(v any) any { return v })
+	assert.EqualError(t, byValue.Validate([]any{1, "a", nil, 1}), "must not contain duplicate items")
+	assert.NoError(t, byValue.Validate([]any{1, "a", nil}))
+	err = byValue.Validate([]any{1, []int{1}})
+	if ie, ok := err.(InternalError); assert.True(t, ok) {
+		assert.EqualError(t, ie.InternalError(), "the key of the item 1 is not comparable")
+	}
+	type tagged struct{ Tag any }
+	err = UniqueBy(func(v any) tagged { return tagged{v} }).Validate([]any{map[string]int{}})
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestItems(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  ItemsRule[int]
+		value []int
+		err   string
+		index any
+	}{
+		{"t1", MinItems[int](2), nil, "must contain at least 2 items", nil},
+		{"t2", MinItems[int](2), []int{1}, "must contain at least 2 items", nil},
+		{"t3", MinItems[int](2), []int{1, 2}, "", nil},
+		{"t4", MinItems[int](0), nil, "", nil},
+		{"t5", MaxItems[int](2), nil, "", nil},
+		{"t6", MaxItems[int](2), []int{1, 2}, "", nil},
+		{"t7", MaxItems[int](2), []int{1, 2, 3, 4}, "must contain no more than 2 items", 2},
+		{"t8", MaxItems[int](1).Error("too many"), []int{1, 2}, "too many", 1},
+	}
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		assertIndex(t, test.index, err, test.tag)
+	}
+
+	err := MinItems[int](1).ErrorObject(NewError("code", "abc")).Validate(nil)
+	assert.Equal(t, "code", err.(Error).Code())
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value []string
+		err   string
+	}{
+		{"t1", nil, ""},
+		{"t2", []string{"read", "admin"}, ""},
+		{"t3", []string{"read", "write"}, "must contain admin"},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, Contains("admin").Validate(test.value), test.tag)
+	}
+
+	err := Contains(1).Error("needs {{.value}}").Validate([]int{2})
+	assert.EqualError(t, err, "needs 1")
+	err = Contains(1).ErrorObject(NewError("code", "abc")).Validate([]int{2})
+	assert.Equal(t, "code", err.(Error).Code())
+}
+
+func TestItemsIn(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value []string
+		err   string
+		index any
+	}{
+		{"t1", nil, "", nil},
+		{"t2", []string{"red", "blue", "red"}, "", nil},
+		{"t3", []string{"red", "pink", "teal"}, "must contain only valid items", 1},
+	}
+	for _, test := range tests {
+		err := ItemsIn("red", "green", "blue").Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		assertIndex(t, test.index, err, test.tag)
+	}
+
+	err := ItemsIn(1).Error("bad").Validate([]int{2})
+	assert.EqualError(t, err, "bad")
+	err = ItemsIn(1).ErrorObject(NewError("code", "abc")).Validate([]int{2})
+	assert.Equal(t, "code", err.(Error).Code())
+}
+
+func TestSorted(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  SortedRule[int]
+		value []int
+		err   string
+		index any
+	}{
+		{"t1", Sorted[int](), nil, "", nil},
+		{"t2", Sorted[int](), []int{1, 2, 2, 3}, "", nil},
+		{"t3", Sorted[int](), []int{1, 3, 2}, "must be sorted in ascending order", 2},
+		{"t4", Sorted[int]().Desc(), []int{3, 2, 2, 1}, "", nil},
+		{"t5", Sorted[int]().Desc(), []int{3, 1, 2}, "must be sorted in descending order", 2},
+		{"t6", Sorted[int]().Error("unsorted"), []int{2, 1}, "unsorted", 1},
+		{"t7", Sorted[int]().Error("unsorted").Desc(), []int{1, 2}, "unsorted", 1},
+		{"t8", Sorted[int]().Desc().Error("unsorted"), []int{1, 2}, "unsorted", 1},
+	}
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		assertIndex(t, test.index, err, test.tag)
+	}
+
+	byLength := SortedFunc(func(a, b string) int { return len(a) - len(b) })
+	assert.NoError(t, byLength.Validate([]string{"b", "aa", "ccc"}))
+	assert.EqualError(t, byLength.Validate([]string{"aa", "b"}), "must be sorted in ascending order")
+
+	err := Sorted[int]().ErrorObject(NewError("code", "abc")).Validate([]int{2, 1})
+	assert.Equal(t, "code", err.(Error).Code())
+	err = Sorted[int]().ErrorObject(NewError("code", "abc")).Desc().Validate([]int{1, 2})
+	assert.Equal(t, "code", err.(Error).Code())
+	err = Sorted[int]().Desc().Validate([]int{1, 2})
+	assert.Equal(t, ErrSortedDescending.Code(), err.(Error).Code())
+}
+
+func TestCollectionRulesInStruct(t *testing.T) {
+	type request struct {
+		Tags   []string
+		Scores []int
+	}
+	r := request{Tags: []string{"a", "b", "a"}, Scores: []int{3, 1}}
+	err := ValidateStruct(&r,
+		Field(&r.Tags, Any(Unique[string]()), Any(MaxItems[string](5))),
+		Field(&r.Scores, Any(Sorted[int]())),
+	)
+	assert.EqualError(t, err, "Scores: must be sorted in ascending order; Tags: must not contain duplicate items.")
+}
+
+func assertIndex(t *testing.T, expected any, err error, tag string) {
+	t.Helper()
+	if expected == nil {
+		return
+	}
+	e, ok := err.(Error)
+	if !assert.True(t, ok, tag) {
+		return
+	}
+	assert.Equal(t, expected, e.Params()["index"], tag)
+}
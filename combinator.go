@@ -0,0 +1,235 @@
+package kv
+
+import (
+	"context"
+)
+
+var (
+	// ErrAnyOf is the error that returns when a value satisfies none of the rules of an AnyOf rule.
+	ErrAnyOf = NewError("validation_any_of", "must satisfy at least one of: {{.errors}}")
+	// ErrNot is the error that returns when a value satisfies the rule negated by a Not rule.
+	ErrNot = NewError("validation_not", "is not allowed")
+)
+
+type (
+	// AnyOfRule is a validation rule that checks if a value satisfies at least one of the specified rules.
+	AnyOfRule[T any] struct {
+		rules []Rule[T]
+		err   Error
+	}
+
+	// AllOfRule is a validation rule that checks if a value satisfies all of the specified rules.
+	AllOfRule[T any] struct {
+		rules []Rule[T]
+	}
+
+	// NotRule is a validation rule that checks if a value does not satisfy the specified rule.
+	NotRule[T any] struct {
+		rule Rule[T]
+		err  Error
+	}
+
+	// ExactlyOneRule is a validation rule that checks if a value satisfies exactly one of the specified rules.
+	ExactlyOneRule[T any] struct {
+		rules []Rule[T]
+		none  Error
+		many  Error
+	}
+)
+
+// AnyOf returns a validation rule that checks if a value satisfies at least one of the given rules.
+// The rules can be either legacy rules or typed rules. For example,
+//
+//	kv.Field(&s.Host, kv.AnyOf(is.IPv4, is.DNSName), kv.Not(kv.In("localhost")))
+//
+// If the value satisfies none of the rules, ErrAnyOf is returned with the errors of all rules
+// given as an ErrorList in the "errors" param. An InternalError returned by any rule stops the validation
+// and is returned immediately.
+func AnyOf[T any](rules ...Rule[T]) AnyOfRule[T] {
+	return AnyOfRule[T]{rules: rules, err: ErrAnyOf}
+}
+
+// Validate checks if the given value is valid or not.
+func (r AnyOfRule[T]) Validate(value T) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r AnyOfRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	if len(r.rules) == 0 {
+		return nil
+	}
+	var errs ErrorList
+	for _, rule := range r.rules {
+		err := validateRule(ctx, rule, value)
+		if err == nil {
+			return nil
+		}
+		if isInternalError(err) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return withParam(r.err, "errors", errs)
+}
+
+// Error sets the error message for the rule.
+func (r AnyOfRule[T]) Error(message string) AnyOfRule[T] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r AnyOfRule[T]) ErrorObject(err Error) AnyOfRule[T] {
+	r.err = err
+	return r
+}
+
+// AllOf returns a validation rule that checks if a value satisfies all of the given rules.
+// The rules are validated in order, and the error of the first failing rule is returned.
+// It behaves like listing the rules one after another, and is meant to be nested in AnyOf or ExactlyOne.
+func AllOf[T any](rules ...Rule[T]) AllOfRule[T] {
+	return AllOfRule[T]{rules: rules}
+}
+
+// Validate checks if the given value is valid or not.
+func (r AllOfRule[T]) Validate(value T) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r AllOfRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	for _, rule := range r.rules {
+		if s, ok := rule.(skipper[T]); ok && s.skipped(ctx, value) {
+			return nil
+		}
+		if err := validateRule(ctx, rule, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Not returns a validation rule that checks if a value does not satisfy the given rule.
+// If the value satisfies the rule, ErrNot is returned. Call Error to describe what is not allowed, for example,
+//
+//	kv.Not(kv.In("localhost")).Error("must not be localhost")
+//
+// An empty value is considered valid, because most rules accept it. An InternalError returned by the rule
+// is returned as is.
+func Not[T any](rule Rule[T]) NotRule[T] {
+	return NotRule[T]{rule: rule, err: ErrNot}
+}
+
+// Validate checks if the given value is valid or not.
+func (r NotRule[T]) Validate(value T) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r NotRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	if v, isNil := Indirect(value); isNil || IsEmpty(v) {
+		return nil
+	}
+	err := validateRule(ctx, r.rule, value)
+	if err == nil {
+		return r.err
+	}
+	if isInternalError(err) {
+		return err
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r NotRule[T]) Error(message string) NotRule[T] {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r NotRule[T]) ErrorObject(err Error) NotRule[T] {
+	r.err = err
+	return r
+}
+
+// ExactlyOne returns a validation rule that checks if a value satisfies exactly one of the given rules.
+// It is the typed counterpart of OneOf with a single rule in each set, and returns the same errors:
+// ErrOneOfNone if the value satisfies none of the rules, and ErrOneOfMultiple with the indices of
+// the satisfied rules given as the "matches" param if it satisfies several of them.
+// An InternalError returned by any rule stops the validation and is returned immediately.
+func ExactlyOne[T any](rules ...Rule[T]) ExactlyOneRule[T] {
+	return ExactlyOneRule[T]{rules: rules, none: ErrOneOfNone, many: ErrOneOfMultiple}
+}
+
+// Validate checks if the given value is valid or not.
+func (r ExactlyOneRule[T]) Validate(value T) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r ExactlyOneRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	return validateOneOf(len(r.rules), func(i int) error {
+		return validateRule(ctx, r.rules[i], value)
+	}, r.none, r.many)
+}
+
+// Error sets the error message for the rule.
+func (r ExactlyOneRule[T]) Error(message string) ExactlyOneRule[T] {
+	r.none = r.none.SetMessage(message)
+	r.many = r.many.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ExactlyOneRule[T]) ErrorObject(err Error) ExactlyOneRule[T] {
+	r.none = err
+	r.many = err
+	return r
+}
+
+func (r AnyOfRule[T]) ruleLists() []namedRules {
+	return alternativeLists(r.rules)
+}
+
+func (r AllOfRule[T]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}}
+}
+
+func (r NotRule[T]) ruleLists() []namedRules {
+	return []namedRules{{rules: []any{r.rule}}}
+}
+
+func (r ExactlyOneRule[T]) ruleLists() []namedRules {
+	return alternativeLists(r.rules)
+}
+
+// alternativeLists returns each of the alternative rules as a list of its own,
+// so that the bounds of different alternatives, such as AnyOf(Max(5), Min(10)), are not checked against each other.
+func alternativeLists[T any](rules []Rule[T]) []namedRules {
+	lists := make([]namedRules, len(rules))
+	for i, rule := range rules {
+		lists[i].rules = []any{rule}
+	}
+	return lists
+}
+
+func (r AnyOfRule[T]) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
+
+func (r AllOfRule[T]) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
+
+func (r NotRule[T]) skipsEmpty() bool {
+	return true
+}
+
+func (r NotRule[T]) referencesFields() bool {
+	return ruleReferencesFields(r.rule)
+}
+
+func (r ExactlyOneRule[T]) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
@@ -0,0 +1,159 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestAnyOf(t *testing.T) {
+	rule := AnyOf(In("abc"), &validateXyz{})
+	tests := []struct {
+		tag   string
+		value any
+		err   string
+	}{
+		{"t1", "abc", ""},
+		{"t2", "xyz", ""},
+		{"t3", "123", "must satisfy at least one of: must be a valid value, error xyz"},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+		assertError(t, test.err, Validate(test.value, rule), test.tag)
+	}
+
+	err := rule.Validate("123")
+	assert.Equal(t, "validation_any_of", err.(Error).Code())
+	assert.EqualError(t, err.(Error).Params()["errors"].(ErrorList), "must be a valid value, error xyz")
+	assert.Nil(t, ErrAnyOf.Params())
+
+	// no rules
+	assert.NoError(t, AnyOf[any]().Validate("abc"))
+
+	// typed rules
+	typed := AnyOf(Max(-10), Min(10))
+	assert.NoError(t, typed.Validate(-20))
+	assert.NoError(t, ValidateValue(20, typed))
+	assert.EqualError(t, typed.Validate(5), "must satisfy at least one of: must be no greater than -10, must be no less than 10")
+	// the errors are only collected when a rule fails
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = typed.Validate(-20)
+	})
+	assert.Equal(t, float64(0), allocs)
+
+	// custom errors
+	assert.EqualError(t, rule.Error("invalid").Validate("123"), "invalid")
+	assert.EqualError(t, rule.ErrorObject(NewError("code", "{{.errors}}!")).Validate("123"), "must be a valid value, error xyz!")
+
+	// internal errors
+	err = AnyOf(&validateInternalError{}, In("internal")).Validate("internal")
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestAllOf(t *testing.T) {
+	rule := AllOf(Required, Length(2, 3))
+	assert.NoError(t, rule.Validate("ab"))
+	assert.EqualError(t, rule.Validate(""), "cannot be blank")
+	assert.EqualError(t, rule.Validate("abcd"), "the length must be between 2 and 3")
+	assert.NoError(t, AllOf(Skip, Required).Validate(""))
+
+	// nested in AnyOf
+	nested := AnyOf(AllOf(In("abc", "xyz"), &validateAbc{}), In("123"))
+	assert.NoError(t, nested.Validate("abc"))
+	assert.NoError(t, nested.Validate("123"))
+	assert.EqualError(t, nested.Validate("xyz"), "must satisfy at least one of: error abc, must be a valid value")
+}
+
+func TestNot(t *testing.T) {
+	rule := Not(In("localhost"))
+	tests := []struct {
+		tag   string
+		value any
+		err   string
+	}{
+		{"t1", "example.com", ""},
+		{"t2", "localhost", "is not allowed"},
+		{"t3", "", ""},
+		{"t4", (*string)(nil), ""},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+	}
+
+	assert.EqualError(t, rule.Error("must not be localhost").Validate("localhost"), "must not be localhost")
+	err := rule.ErrorObject(NewError("code", "abc")).Validate("localhost")
+	assert.Equal(t, "code", err.(Error).Code())
+
+	// typed rules
+	assert.NoError(t, Not(Min(10)).Validate(5))
+	assert.EqualError(t, Not(Min(10)).Validate(15), "is not allowed")
+
+	// internal errors
+	err = Not[any](&validateInternalError{}).Validate("internal")
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestExactlyOne(t *testing.T) {
+	rule := ExactlyOne(Length(0, 3), In("abcd", "abc"))
+	tests := []struct {
+		tag     string
+		value   any
+		err     string
+		matches any
+	}{
+		{"t1", "ab", "", nil},
+		{"t2", "abcd", "", nil},
+		{"t3", "abc", "must match exactly one of the allowed schemas", "0, 1"},
+		{"t4", "abcde", "must match one of the allowed schemas", nil},
+	}
+	for _, test := range tests {
+		err := rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		if test.matches != nil {
+			assert.Equal(t, test.matches, err.(Error).Params()["matches"], test.tag)
+		}
+	}
+
+	// the errors are shared with OneOf
+	assert.True(t, errors.Is(rule.Validate("abc"), ErrOneOfMultiple))
+	assert.True(t, errors.Is(rule.Validate("abcde"), ErrOneOfNone))
+	typed := ExactlyOne(Min(10), Max(20))
+	assert.NoError(t, typed.Validate(5))
+	assert.True(t, errors.Is(typed.Validate(15), ErrOneOfMultiple))
+
+	assert.EqualError(t, rule.Error("ambiguous").Validate("abc"), "ambiguous")
+	err := rule.ErrorObject(NewError("code", "abc")).Validate("abc")
+	assert.Equal(t, "code", err.(Error).Code())
+
+	// internal errors
+	err = ExactlyOne(Required, &validateInternalError{}).Validate("internal")
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestCombinatorsWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	ctxRule := WithContext(func(ctx context.Context, value any) error {
+		if value != ctx.Value(contains) {
+			return errors.New("unexpected value")
+		}
+		return nil
+	})
+	assert.NoError(t, ValidateWithContext(ctx, "abc", AnyOf(In("xyz"), ctxRule)))
+	assert.NoError(t, ValidateWithContext(ctx, "abc", AllOf(Required, ctxRule)))
+	assert.NoError(t, ValidateWithContext(ctx, "xyz", Not(ctxRule)))
+	assert.EqualError(t, ValidateWithContext(ctx, "abc", Not(ctxRule)), "is not allowed")
+	assert.NoError(t, ValidateWithContext(ctx, "abc", ExactlyOne(In("xyz"), ctxRule)))
+
+	// in a struct
+	type server struct {
+		Host string
+	}
+	s := server{Host: "localhost"}
+	err := ValidateStruct(&s, Field(&s.Host, AnyOf(In("localhost", "example.com")), Not(In("localhost"))))
+	assert.EqualError(t, err, "Host: is not allowed.")
+}
@@ -0,0 +1,281 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Validator is a compiled list of rules for values of type T, as returned by Compile.
+	// It is immutable and safe for concurrent use.
+	Validator[T any] struct {
+		rules []Rule[T]
+		steps []compiledRule[T]
+		// validatable and validatableWithContext tell if T may implement Validatable and ValidatableWithContext.
+		validatable, validatableWithContext bool
+	}
+
+	compiledRule[T any] struct {
+		rule    Rule[T]
+		ctxRule TypedRuleWithContext[T]
+		rawRule rawRule[T]
+		skipper skipper[T]
+	}
+
+	// CompileError is the error returned by Compile when the rules contain mistakes,
+	// such as Length(10, 5), that would make them misbehave silently when values are validated.
+	CompileError []CompileIssue
+
+	// CompileIssue is a mistake found in the rules by Compile.
+	CompileIssue struct {
+		// Path is the path of the field whose rules contain the mistake, if any.
+		Path Path `json:"path,omitempty"`
+		// Rule is the type of the rule containing the mistake.
+		Rule string `json:"rule"`
+		// Message describes the mistake.
+		Message string `json:"message"`
+	}
+
+	// staticChecker is implemented by rules whose configuration can be checked by Compile.
+	staticChecker interface {
+		// checkStatic returns the description of a mistake in the configuration of the rule, or "" if there is none.
+		checkStatic() string
+	}
+
+	// boundRule is implemented by rules checking a lower or upper bound, such as those returned by Min and Max,
+	// so that Compile can check that the bounds of a rule list do not contradict each other.
+	boundRule interface {
+		bound() (value any, lower, exclusive bool)
+	}
+
+	// ruleSet is implemented by rules holding other rules, so that Compile can check the nested rules.
+	ruleSet interface {
+		ruleLists() []namedRules
+	}
+
+	// namedRules is a list of rules nested in a rule. The name is the path element of the value
+	// validated by the rules, such as a struct field name, or "" if the value has no name of its own.
+	namedRules struct {
+		name  string
+		rules []any
+	}
+)
+
+// Compile checks the given rules and compiles them into a Validator for values of type T.
+// Build the validator once, for example as a package variable, and reuse it. For example,
+//
+//	var ageValidator = kv.MustCompile(kv.Min(18), kv.Max(130))
+//
+//	err := ageValidator.Validate(age)
+//
+// A struct rule set built by Struct is compiled as a rule for pointers to the struct:
+//
+//	var customerValidator = kv.MustCompile[*Customer](kv.Struct[Customer](...))
+//
+// Compile reports the following mistakes as a CompileError: a Length or RuneLength whose min is greater than its max,
+// a Min greater than a Max in the same rule list, a Date layout that cannot parse the dates it formats,
+// an In or NotIn with values of mixed types, and a Match with a nil regular expression. The rules nested in other
+// rules, such as When, Each, EachOf, EachEntry, AllOf, AnyOf, Not, Warn, Map, MapOf, Discriminated, DecodeJSON and the fields
+// of Struct, are checked as well. The alternatives of AnyOf, ExactlyOne and OneOf are checked apart from each other.
+//
+// The dispatch that ValidateValue performs on every call, such as finding the context-aware rules and
+// checking if T implements Validatable, is done once by Compile.
+func Compile[T any](rules ...Rule[T]) (*Validator[T], error) {
+	if issues := checkRules(nil, anyRules(rules)); len(issues) > 0 {
+		return nil, CompileError(issues)
+	}
+
+	v := &Validator[T]{rules: slices.Clone(rules), steps: make([]compiledRule[T], len(rules))}
+	for i, rule := range rules {
+		v.steps[i].rule = rule
+		v.steps[i].ctxRule, _ = rule.(TypedRuleWithContext[T])
+		v.steps[i].rawRule, _ = rule.(rawRule[T])
+		v.steps[i].skipper, _ = rule.(skipper[T])
+	}
+	// the dynamic type of an interface value is only known when the value is validated
+	t := reflect.TypeFor[T]()
+	v.validatable = t.Kind() == reflect.Interface || t.Implements(validatableType)
+	v.validatableWithContext = t.Kind() == reflect.Interface || t.Implements(validatableWithContextType)
+	return v, nil
+}
+
+// MustCompile is like Compile but panics if the rules contain mistakes.
+// It simplifies the initialization of package variables holding validators.
+func MustCompile[T any](rules ...Rule[T]) *Validator[T] {
+	v, err := Compile(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Validate validates the given value like ValidateValue does with the compiled rules.
+func (v *Validator[T]) Validate(value T) error {
+	return v.validate(nil, value)
+}
+
+// ValidateWithContext validates the given value with the given context like ValidateValueWithContext does
+// with the compiled rules.
+func (v *Validator[T]) ValidateWithContext(ctx context.Context, value T) error {
+	return Translate(ctx, v.validateRaw(ctx, value))
+}
+
+func (v *Validator[T]) validateRaw(ctx context.Context, value T) error {
+	if traceFromContext(ctx) != nil {
+		return validateValueWithContext(ctx, value, v.rules...)
+	}
+	return v.validate(ctx, value)
+}
+
+// validate validates the value with the compiled rules. A nil context means the value is validated without context.
+func (v *Validator[T]) validate(ctx context.Context, value T) error {
+	for _, step := range v.steps {
+		if step.skipper != nil {
+			sctx := ctx
+			if sctx == nil {
+				sctx = context.TODO()
+			}
+			if step.skipper.skipped(sctx, value) {
+				return nil
+			}
+		}
+		var err error
+		if step.rawRule != nil && ctx != nil {
+			err = step.rawRule.validateRaw(ctx, value)
+		} else if step.ctxRule != nil && ctx != nil {
+			err = step.ctxRule.ValidateWithContext(ctx, value)
+		} else {
+			err = step.rule.Validate(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx != nil && v.validatableWithContext {
+		if vc, ok := typedValidatable[T, ValidatableWithContext](value); ok {
+			return vc.ValidateWithContext(ctx)
+		}
+	}
+	if v.validatable {
+		if vp, ok := typedValidatable[T, Validatable](value); ok {
+			return vp.Validate()
+		}
+	}
+	return nil
+}
+
+// Error returns the issues separated by semicolons.
+func (e CompileError) Error() string {
+	s := make([]string, len(e))
+	for i, issue := range e {
+		s[i] = issue.Error()
+	}
+	return "invalid rules: " + strings.Join(s, "; ")
+}
+
+// Error returns the issue in the form of "path: rule: message".
+func (i CompileIssue) Error() string {
+	s := i.Rule + ": " + i.Message
+	if len(i.Path) > 0 {
+		s = i.Path.String() + ": " + s
+	}
+	return s
+}
+
+// checkRules checks a list of rules validating the value at the given path, and the rules nested in them.
+func checkRules(path Path, rules []any) []CompileIssue {
+	var (
+		issues       []CompileIssue
+		lower, upper []any
+	)
+	for _, rule := range rules {
+		rule = unwrapRule(rule)
+		if c, ok := rule.(staticChecker); ok {
+			if msg := c.checkStatic(); msg != "" {
+				issues = append(issues, CompileIssue{Path: path, Rule: ruleName(rule), Message: msg})
+			}
+		}
+		if b, ok := rule.(boundRule); ok {
+			if _, isLower, _ := b.bound(); isLower {
+				lower = append(lower, rule)
+			} else {
+				upper = append(upper, rule)
+			}
+		}
+		if s, ok := rule.(ruleSet); ok {
+			for _, l := range s.ruleLists() {
+				p := path
+				if l.name != "" {
+					p = append(path[:len(path):len(path)], l.name)
+				}
+				issues = append(issues, checkRules(p, l.rules)...)
+			}
+		}
+	}
+
+	for _, lr := range lower {
+		min, _, minExclusive := lr.(boundRule).bound()
+		for _, ur := range upper {
+			max, _, maxExclusive := ur.(boundRule).bound()
+			c, ok := compareValues(min, max)
+			if !ok || c < 0 || c == 0 && !minExclusive && !maxExclusive {
+				continue
+			}
+			issues = append(issues, CompileIssue{
+				Path:    path,
+				Rule:    ruleName(lr),
+				Message: fmt.Sprintf("the minimum %v is not less than the maximum %v, so no value is valid", min, max),
+			})
+		}
+	}
+	return issues
+}
+
+// anyRules returns the rules as a list of values of any type.
+func anyRules[T any](rules []Rule[T]) []any {
+	list := make([]any, len(rules))
+	for i, rule := range rules {
+		list[i] = rule
+	}
+	return list
+}
+
+// checkLayout checks if a date layout can parse the dates it formats.
+// Two dates are formatted, so that the layouts whose elements run together with one or two digits are detected.
+func checkLayout(layout string) string {
+	dates := []time.Time{
+		time.Date(2009, time.November, 17, 20, 34, 58, 0, time.UTC),
+		time.Date(2009, time.March, 4, 5, 6, 7, 0, time.UTC),
+	}
+	if dates[0].Format(layout) == layout {
+		return fmt.Sprintf("the layout %q contains no date or time element", layout)
+	}
+	for _, t := range dates {
+		s := t.Format(layout)
+		if p, err := time.Parse(layout, s); err != nil || p.Format(layout) != s {
+			return fmt.Sprintf("the layout %q cannot parse the dates it formats, such as %q", layout, s)
+		}
+	}
+	return ""
+}
+
+// checkTypes checks if the values are of the same type, ignoring nil values.
+func checkTypes(values []any) string {
+	var first reflect.Type
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if t := reflect.TypeOf(v); first == nil {
+			first = t
+		} else if t != first {
+			return fmt.Sprintf("the values are of mixed types %v and %v", first, t)
+		}
+	}
+	return ""
+}
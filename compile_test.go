@@ -0,0 +1,221 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type compileOrder struct {
+	ID    string   `json:"id"`
+	Qty   int      `json:"qty"`
+	Notes []string `json:"notes"`
+}
+
+type compileCode string
+
+func (c compileCode) Validate() error {
+	if c == "bad" {
+		return errors.New("is bad")
+	}
+	return nil
+}
+
+func (c compileCode) ValidateWithContext(ctx context.Context) error {
+	if ctx.Value(roleKey{}) == "admin" {
+		return nil
+	}
+	return c.Validate()
+}
+
+func compileMessages(err error) []any {
+	var messages []any
+	for _, issue := range err.(CompileError) {
+		messages = append(messages, issue.Error())
+	}
+	return messages
+}
+
+func TestCompile(t *testing.T) {
+	v, err := Compile(Min(18), Max(130))
+	assert.NoError(t, err)
+	assert.NoError(t, v.Validate(20))
+	assert.EqualError(t, v.Validate(10), "must be no less than 18")
+	assert.EqualError(t, v.ValidateWithContext(WithLocale(context.Background(), "fr"), 140), "doit être inférieur ou égal à 130")
+
+	// equal inclusive bounds are valid
+	_, err = Compile(Min(5), Max(5))
+	assert.NoError(t, err)
+
+	// skip rules and context-aware rules
+	s := MustCompile(Typed[string](Skip.WhenFunc(func(ctx context.Context) bool {
+		return ctx.Value(roleKey{}) == "admin"
+	})), Typed[string](Required))
+	admin := context.WithValue(context.Background(), roleKey{}, "admin")
+	assert.NoError(t, s.ValidateWithContext(admin, ""))
+	assert.EqualError(t, s.ValidateWithContext(context.Background(), ""), "cannot be blank")
+	assert.EqualError(t, s.Validate(""), "cannot be blank")
+
+	// the compiled rules cannot be changed through the given slice
+	rules := []Rule[string]{Typed[string](Required)}
+	r := MustCompile(rules...)
+	rules[0] = Typed[string](Length(0, 0))
+	assert.EqualError(t, r.Validate(""), "cannot be blank")
+}
+
+func TestCompileValidatable(t *testing.T) {
+	v := MustCompile[compileCode]()
+	assert.EqualError(t, v.Validate("bad"), "is bad")
+	assert.EqualError(t, v.ValidateWithContext(context.Background(), "bad"), "is bad")
+	admin := context.WithValue(context.Background(), roleKey{}, "admin")
+	assert.NoError(t, v.ValidateWithContext(admin, "bad"))
+
+	// the dynamic type of an interface value is checked when the value is validated
+	a := MustCompile[any]()
+	assert.EqualError(t, a.Validate(compileCode("bad")), "is bad")
+	assert.NoError(t, a.ValidateWithContext(admin, compileCode("bad")))
+	assert.NoError(t, a.Validate("bad"))
+}
+
+func TestCompileStruct(t *testing.T) {
+	v, err := Compile[*compileOrder](Struct[compileOrder](
+		FieldOf("id", func(o *compileOrder) *string { return &o.ID }, Typed[string](Required), Typed[string](Length(2, 10))),
+		FieldOf("qty", func(o *compileOrder) *int { return &o.Qty }, Min(1), Max(50)),
+	))
+	assert.NoError(t, err)
+	assert.EqualError(t, v.Validate(&compileOrder{Qty: -1}), "id: cannot be blank; qty: must be no less than 1.")
+	assert.NoError(t, v.Validate(&compileOrder{ID: "ab", Qty: 1}))
+	assert.NoError(t, v.Validate(nil))
+
+	// tracing is still supported
+	ctx, trace := WithTrace(context.Background())
+	assert.NoError(t, v.ValidateWithContext(ctx, &compileOrder{ID: "ab", Qty: 1}))
+	assert.Equal(t, 5, len(trace.Entries()))
+
+	// concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := v.ValidateWithContext(context.Background(), &compileOrder{ID: "ab", Qty: j}); (err == nil) != (j <= 50) {
+					t.Errorf("unexpected result for %d: %v", j, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCompileError(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rules []Rule[any]
+		err   string
+	}{
+		{"length", []Rule[any]{Length(10, 5)}, "kv.LengthRule: the minimum length 10 is greater than the maximum length 5, so no value is valid"},
+		{"length without max", []Rule[any]{Length(10, 0)}, ""},
+		{"negative length", []Rule[any]{RuneLength(-1, 5)}, "kv.LengthRule: the length range -1-5 is negative"},
+		{"min above max", []Rule[any]{Any(Min(10)), Any(Max(5))}, "kv.ThresholdRule[int]: the minimum 10 is not less than the maximum 5, so no value is valid"},
+		{"exclusive bounds", []Rule[any]{Any(Min(5).Exclusive()), Any(Max(5))}, "kv.ThresholdRule[int]: the minimum 5 is not less than the maximum 5, so no value is valid"},
+		{"time bounds", []Rule[any]{Any(MinTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))), Any(MaxTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)))}, "kv.TimeThresholdRule: the minimum 2020-01-01 00:00:00 +0000 UTC is not less than the maximum 2019-01-01 00:00:00 +0000 UTC, so no value is valid"},
+		{"date layout", []Rule[any]{Date("yyyy-mm-dd")}, `kv.DateRule: the layout "yyyy-mm-dd" contains no date or time element`},
+		{"date digits", []Rule[any]{Date("1/22006")}, `kv.DateRule: the layout "1/22006" cannot parse the dates it formats, such as "3/42009"`},
+		{"date", []Rule[any]{Date(time.RFC3339), Date("2006-01-02"), Date(time.Kitchen)}, ""},
+		{"in", []Rule[any]{In("a", 1)}, "kv.InRule: the values are of mixed types string and int"},
+		{"in with nil", []Rule[any]{In("a", nil, "b")}, ""},
+		{"not in", []Rule[any]{NotIn(1, int64(2))}, "kv.NotInRule: the values are of mixed types int and int64"},
+		{"match", []Rule[any]{Match(nil)}, "kv.MatchRule: the regular expression is nil"},
+		{"match valid", []Rule[any]{Match(regexp.MustCompile("^a"))}, ""},
+		{"when", []Rule[any]{When(true, Length(3, 1)).Else(Match(nil))}, "kv.LengthRule: the minimum length 3 is greater than the maximum length 1, so no value is valid; kv.MatchRule: the regular expression is nil"},
+		{"each", []Rule[any]{Each(In(1, "a"))}, "kv.InRule: the values are of mixed types int and string"},
+		{"nested lists", []Rule[any]{Any(Max(5)), Each(Any(Min(10)))}, ""},
+	}
+	for _, test := range tests {
+		_, err := Compile(test.rules...)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, "invalid rules: "+test.err, test.tag)
+		}
+	}
+}
+
+func TestCompileErrorNested(t *testing.T) {
+	const (
+		lengthIssue = "kv.LengthRule: the minimum length 3 is greater than the maximum length 1, so no value is valid"
+		boundsIssue = "kv.ThresholdRule[int]: the minimum 10 is not less than the maximum 5, so no value is valid"
+	)
+	tests := []struct {
+		tag   string
+		rules []Rule[any]
+		err   string
+	}{
+		{"any of", []Rule[any]{AnyOf(Length(3, 1), Match(nil))}, lengthIssue + "; kv.MatchRule: the regular expression is nil"},
+		{"any of alternatives", []Rule[any]{Any(AnyOf(Max(5), Min(10)))}, ""},
+		{"any of all of", []Rule[any]{Any(AnyOf[int](AllOf(Min(10), Max(5))))}, boundsIssue},
+		{"exactly one", []Rule[any]{ExactlyOne(Required, Length(3, 1))}, lengthIssue},
+		{"exactly one alternatives", []Rule[any]{Any(ExactlyOne(Max(5), Min(10)))}, ""},
+		{"not", []Rule[any]{Not(Length(3, 1))}, lengthIssue},
+		{"warn", []Rule[any]{Warn(Length(3, 1))}, lengthIssue},
+		{"map", []Rule[any]{Map(Key("name", Length(3, 1)))}, "name: " + lengthIssue},
+		{"map of key", []Rule[any]{Any(MapOf[string, string]().Key("name", Typed[string](Length(3, 1))))}, "name: " + lengthIssue},
+		{"map of pattern", []Rule[any]{Any(MapOf[string, int]().Pattern(regexp.MustCompile("^x"), Min(10), Max(5)))}, boundsIssue},
+		{"map of extra", []Rule[any]{Any(MapOf[string, int]().Extra(Min(10), Max(5)))}, boundsIssue},
+		{"each entry", []Rule[any]{Any(EachEntry([]Rule[string]{Typed[string](Length(3, 1))}, []Rule[int]{Min(10), Max(5)}))}, lengthIssue + "; " + boundsIssue},
+		{"each seq", []Rule[any]{Any(EachSeq(Min(10), Max(5)))}, boundsIssue},
+		{"each seq2", []Rule[any]{Any(EachSeq2([]Rule[int]{Min(10), Max(5)}, []Rule[string]{Typed[string](Length(3, 1))}))}, boundsIssue + "; " + lengthIssue},
+		{"discriminated", []Rule[any]{Discriminated("type").Variant("a", Length(3, 1)).Variant("b", Match(nil))}, lengthIssue + "; kv.MatchRule: the regular expression is nil"},
+		{"one of", []Rule[any]{OneOf([]Rule[any]{Any(Max(5))}, []Rule[any]{Any(Min(10))})}, ""},
+		{"one of set", []Rule[any]{OneOf([]Rule[any]{Any(Min(10)), Any(Max(5))})}, boundsIssue},
+		{"decode json", []Rule[any]{DecodeJSON[map[string]any](Map(Key("name", Length(3, 1))))}, "name: " + lengthIssue},
+	}
+	for _, test := range tests {
+		_, err := Compile(test.rules...)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, "invalid rules: "+test.err, test.tag)
+		}
+	}
+}
+
+func TestCompileErrorStruct(t *testing.T) {
+	type embedded struct{ Code string }
+	type order struct {
+		compileOrder
+		embedded
+	}
+	_, err := Compile[*order](Struct[order](
+		FieldOf("id", func(o *order) *string { return &o.ID }, Typed[string](Length(5, 2))),
+		FieldOf("qty", func(o *order) *int { return &o.Qty }, AllOf(Min(10), Max(1))),
+		FieldOf("notes", func(o *order) *[]string { return &o.Notes }, EachOf(Typed[string](Match(nil)))),
+		FieldOf("embedded", func(o *order) *embedded { return &o.embedded }, Typed[embedded](In(1, "x"))).Embedded(),
+	))
+	assert.Equal(t, []any{
+		"id: kv.LengthRule: the minimum length 5 is greater than the maximum length 2, so no value is valid",
+		"qty: kv.ThresholdRule[int]: the minimum 10 is not less than the maximum 1, so no value is valid",
+		"notes: kv.MatchRule: the regular expression is nil",
+		"kv.InRule: the values are of mixed types int and string",
+	}, compileMessages(err))
+
+	b, err := json.Marshal(err)
+	assert.NoError(t, err)
+	var decoded []map[string]any
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, []any{"id"}, decoded[0]["path"])
+	assert.Equal(t, "kv.LengthRule", decoded[0]["rule"])
+	assert.Nil(t, decoded[3]["path"])
+
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+	MustCompile(Length(2, 1))
+}
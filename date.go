@@ -5,6 +5,7 @@
 package kv
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -101,3 +102,17 @@ func (r DateRule) Validate(value any) error {
 
 	return nil
 }
+
+func (r DateRule) checkStatic() string {
+	if msg := checkLayout(r.layout); msg != "" {
+		return msg
+	}
+	if !r.min.IsZero() && !r.max.IsZero() && r.min.After(r.max) {
+		return fmt.Sprintf("the minimum date %v is after the maximum date %v, so no value is valid", r.min, r.max)
+	}
+	return ""
+}
+
+func (r DateRule) skipsEmpty() bool {
+	return true
+}
@@ -0,0 +1,269 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// ErrDefaultType is the error that a default value cannot be assigned to the field because of its type.
+var ErrDefaultType = errors.New("the default value cannot be assigned to the field because of its type")
+
+type (
+	// DefaultRule is a transform rule that sets a struct field to a default value if the field is zero.
+	// Please refer to TransformRule for how transform rules are run.
+	DefaultRule struct {
+		value func(ctx context.Context) any
+	}
+
+	// TypedDefaultRule is a transform rule that sets a struct field of type T to a default value if the field is zero.
+	TypedDefaultRule[T any] struct {
+		value T
+	}
+
+	// TransformerWithContext is implemented by transform rules that need the context of the validation.
+	TransformerWithContext interface {
+		TransformWithContext(ctx context.Context, ptr any) error
+	}
+
+	// DefaultsPlan records the default values that would be applied by a dry run started by PlanDefaults.
+	// It is safe for concurrent use.
+	DefaultsPlan struct {
+		mu       sync.Mutex
+		defaults []PlannedDefault
+	}
+
+	// PlannedDefault is a default value that would be applied to a field, as recorded by DefaultsPlan.
+	PlannedDefault struct {
+		Path  Path `json:"path"`
+		Value any  `json:"value"`
+	}
+
+	defaultsPlanKey struct{}
+)
+
+// Default returns a transform rule that sets a struct field to the given value if the field is zero,
+// so that the rules following it validate the default value. For example,
+//
+//	kv.Field(&c.Port, kv.Default(8080), kv.Min(1), kv.Max(65535))
+//
+// The value is converted to the type of the field if needed. A number is only converted if it keeps its value,
+// so Default(300) cannot be assigned to a uint8 field, nor Default(0.5) to an int field. If the field is a pointer,
+// it is set to a pointer to the value. If the value cannot be assigned to the field, an InternalError is returned.
+// Slices and maps are copied, so that the fields set by the same rule do not share them.
+func Default(value any) DefaultRule {
+	return DefaultRule{value: func(context.Context) any { return value }}
+}
+
+// DefaultFunc returns a transform rule that sets a struct field to the value returned by the given function
+// if the field is zero. The function is only called when the field is zero, and it receives the context
+// of the validation, so that the default value can depend on it. If the function returns nil, the field is
+// left unchanged. Please refer to Default for the details.
+func DefaultFunc(f func(ctx context.Context) any) DefaultRule {
+	return DefaultRule{value: f}
+}
+
+// DefaultFromContext returns a transform rule that sets a struct field to the value stored in the context
+// of the validation under the given key, if the field is zero and the context has such a value.
+// Please refer to Default for the details.
+func DefaultFromContext(key any) DefaultRule {
+	return DefaultRule{value: func(ctx context.Context) any {
+		if ctx == nil {
+			return nil
+		}
+		return ctx.Value(key)
+	}}
+}
+
+// Validate does nothing, because a value cannot be modified without a pointer to it.
+func (r DefaultRule) Validate(any) error {
+	return nil
+}
+
+// Transform sets the value that the given pointer points to to the default value if it is zero.
+func (r DefaultRule) Transform(ptr any) error {
+	return r.TransformWithContext(context.TODO(), ptr)
+}
+
+// TransformWithContext sets the value that the given pointer points to to the default value if it is zero.
+func (r DefaultRule) TransformWithContext(ctx context.Context, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return NewInternalError(ErrDefaultType)
+	}
+	if v = v.Elem(); !v.IsZero() {
+		return nil
+	}
+	value := r.value(ctx)
+	if value == nil {
+		return nil
+	}
+	dv, ok := assignableValue(reflect.ValueOf(value), v.Type())
+	if !ok {
+		return NewInternalError(ErrDefaultType)
+	}
+	v.Set(copyValue(dv))
+	recordDefault(ctx, value)
+	return nil
+}
+
+// DefaultOf returns a transform rule that sets a struct field of type T to the given value if the field is zero.
+// It is the typed counterpart of Default, for example,
+//
+//	kv.FieldOf("port", func(c *Config) *int { return &c.Port }, kv.DefaultOf(8080), kv.Min(1))
+func DefaultOf[T any](value T) TypedDefaultRule[T] {
+	return TypedDefaultRule[T]{value: value}
+}
+
+// Validate does nothing, because a value cannot be modified without a pointer to it.
+func (r TypedDefaultRule[T]) Validate(T) error {
+	return nil
+}
+
+// Transform sets the value that the given pointer points to to the default value if it is zero.
+func (r TypedDefaultRule[T]) Transform(ptr any) error {
+	return r.TransformWithContext(context.TODO(), ptr)
+}
+
+// TransformWithContext sets the value that the given pointer points to to the default value if it is zero.
+func (r TypedDefaultRule[T]) TransformWithContext(ctx context.Context, ptr any) error {
+	p, ok := ptr.(*T)
+	if !ok || p == nil {
+		return NewInternalError(ErrDefaultType)
+	}
+	if v := reflect.ValueOf(p).Elem(); v.IsZero() {
+		v.Set(copyValue(reflect.ValueOf(&r.value).Elem()))
+		recordDefault(ctx, r.value)
+	}
+	return nil
+}
+
+// PlanDefaults returns a copy of the context that starts a dry run of the default rules.
+// When a struct is validated with the returned context, the default rules record the default values
+// they would apply in the returned DefaultsPlan, and no field is modified. The rules following a default rule
+// still validate the default value, so that the validation result is the same as without the dry run. For example,
+//
+//	ctx, plan := kv.PlanDefaults(ctx)
+//	err := kv.ValidateWithContext(ctx, &cfg)
+//	for _, d := range plan.Defaults() {
+//	    fmt.Printf("%v would default to %v\n", d.Path, d.Value)
+//	}
+func PlanDefaults(ctx context.Context) (context.Context, *DefaultsPlan) {
+	plan := &DefaultsPlan{}
+	return context.WithValue(ctx, defaultsPlanKey{}, plan), plan
+}
+
+// Defaults returns the default values recorded so far, in the order they were recorded.
+func (p *DefaultsPlan) Defaults() []PlannedDefault {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.defaults)
+}
+
+// defaultsPlanFromContext returns the DefaultsPlan of a dry run started by PlanDefaults, if any.
+func defaultsPlanFromContext(ctx context.Context) *DefaultsPlan {
+	if ctx == nil {
+		return nil
+	}
+	plan, _ := ctx.Value(defaultsPlanKey{}).(*DefaultsPlan)
+	return plan
+}
+
+// recordDefault records a default value applied to the field being validated if a dry run is in progress.
+func recordDefault(ctx context.Context, value any) {
+	if plan := defaultsPlanFromContext(ctx); plan != nil {
+		plan.mu.Lock()
+		plan.defaults = append(plan.defaults, PlannedDefault{Path: slices.Clone(pathFromContext(ctx)), Value: value})
+		plan.mu.Unlock()
+	}
+}
+
+// assignableValue returns the value converted so that it can be assigned to a variable of type t.
+// If t is a pointer type, the value is converted to the element type and a pointer to it is returned.
+func assignableValue(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	switch {
+	case v.Type().AssignableTo(t):
+		return v, true
+	case v.Kind() == t.Kind() && v.CanConvert(t):
+		return v.Convert(t), true
+	case isNumber(v.Kind()) && isNumber(t.Kind()):
+		return convertNumber(v, t)
+	case t.Kind() == reflect.Ptr:
+		if ev, ok := assignableValue(v, t.Elem()); ok {
+			p := reflect.New(t.Elem())
+			p.Elem().Set(ev)
+			return p, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// convertNumber converts a number to the numeric type t. It reports false if the number does not keep its value,
+// such as a number overflowing t, a negative number converted to an unsigned type, or a floating-point number
+// with a fractional part converted to an integer type. Floating-point numbers are rounded to the precision of t.
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	cv := v.Convert(t)
+	if isNegative(v) != isNegative(cv) {
+		return reflect.Value{}, false
+	}
+	if v.CanFloat() && cv.CanFloat() {
+		if cv.OverflowFloat(v.Float()) {
+			return reflect.Value{}, false
+		}
+		return cv, true
+	}
+	if !cv.Convert(v.Type()).Equal(v) {
+		return reflect.Value{}, false
+	}
+	return cv, true
+}
+
+// isNegative checks if a number is negative.
+func isNegative(v reflect.Value) bool {
+	switch {
+	case v.CanInt():
+		return v.Int() < 0
+	case v.CanFloat():
+		return v.Float() < 0
+	}
+	return false
+}
+
+// copyValue returns a copy of a value in which the slices, maps and arrays are copied recursively,
+// so that the copy shares none of them with the value.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	}
+	return v
+}
+
+// isNumber checks if the kind is an integer or a floating-point number.
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
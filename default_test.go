@@ -0,0 +1,212 @@
+package kv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type defaultConfig struct {
+	Host    string
+	Port    uint16
+	Region  string
+	Timeout *float64
+	Retries int
+	DB      *defaultDBConfig
+}
+
+type defaultDBConfig struct {
+	Driver string
+	Pool   int
+}
+
+func (c *defaultDBConfig) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, c,
+		Field(&c.Driver, Default("postgres"), In("postgres", "mysql")),
+		Field(&c.Pool, Default(10), Any(Min(1))),
+	)
+}
+
+type regionKey struct{}
+
+func (c *defaultConfig) rules() []*FieldRules {
+	return []*FieldRules{
+		Field(&c.Host, Default("localhost"), Required),
+		Field(&c.Port, Default(8080), Any(Min(uint16(1024)))),
+		Field(&c.Region, DefaultFromContext(regionKey{}), Required),
+		Field(&c.Timeout, Default(2.5)),
+		Field(&c.Retries, DefaultFunc(func(ctx context.Context) any { return 3 }), Any(Max(5))),
+		Field(&c.DB),
+	}
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.WithValue(context.Background(), regionKey{}, "eu-west-1")
+	c := defaultConfig{Host: "example.com", Retries: 7, DB: &defaultDBConfig{}}
+	err := ValidateStructWithContext(ctx, &c, c.rules()...)
+	assert.EqualError(t, err, "Retries: must be no greater than 5.")
+	assert.Equal(t, "example.com", c.Host)
+	assert.Equal(t, uint16(8080), c.Port)
+	assert.Equal(t, "eu-west-1", c.Region)
+	assert.Equal(t, 2.5, *c.Timeout)
+	assert.Equal(t, 7, c.Retries)
+	assert.Equal(t, "postgres", c.DB.Driver)
+	assert.Equal(t, 10, c.DB.Pool)
+
+	// no value in the context
+	c = defaultConfig{}
+	err = ValidateStruct(&c, c.rules()...)
+	assert.EqualError(t, err, "Region: cannot be blank.")
+	assert.Equal(t, 3, c.Retries)
+
+	// rules before a default see the zero value
+	c = defaultConfig{}
+	err = ValidateStruct(&c, Field(&c.Host, Required, Default("localhost")))
+	assert.EqualError(t, err, "Host: cannot be blank.")
+
+	// type errors
+	c = defaultConfig{}
+	err = ValidateStruct(&c, Field(&c.Host, Default(123)))
+	assert.Equal(t, ErrDefaultType, err.(InternalError).InternalError())
+	assert.Equal(t, ErrDefaultType, Default(1).Transform(nil).(InternalError).InternalError())
+
+	// no effect without a pointer
+	assert.NoError(t, Validate("", Default("abc")))
+}
+
+func TestDefaultOf(t *testing.T) {
+	rules := Struct[defaultConfig](
+		FieldOf("host", func(c *defaultConfig) *string { return &c.Host }, DefaultOf("localhost"), Typed[string](Length(0, 5))),
+		FieldOf("retries", func(c *defaultConfig) *int { return &c.Retries }, DefaultOf(3), Max(5)),
+	)
+	c := defaultConfig{}
+	assert.EqualError(t, rules.Validate(&c), "host: the length must be no more than 5.")
+	assert.Equal(t, "localhost", c.Host)
+	assert.Equal(t, 3, c.Retries)
+
+	// adapted to a legacy rule
+	c = defaultConfig{}
+	assert.NoError(t, ValidateStruct(&c, Field(&c.Retries, Any(DefaultOf(3)), Any(Max(5)))))
+	assert.Equal(t, 3, c.Retries)
+
+	err := DefaultOf(3).Transform(new(string))
+	assert.Equal(t, ErrDefaultType, err.(InternalError).InternalError())
+}
+
+func TestDefaultConversion(t *testing.T) {
+	var v struct {
+		U8    uint8
+		U     uint
+		I     int
+		I8    int8
+		F32   float32
+		F64   float64
+		PI    *int
+		Port  defaultPort
+		Ratio float64
+	}
+	tests := []struct {
+		tag   string
+		ptr   any
+		value any
+		want  any
+	}{
+		{"t1.1", &v.U8, 255, uint8(255)},
+		{"t1.2", &v.U8, 300, nil},
+		{"t1.3", &v.U, -1, nil},
+		{"t1.4", &v.I8, int64(-129), nil},
+		{"t1.5", &v.I, uint64(1 << 63), nil},
+		{"t1.6", &v.Port, 8080, defaultPort(8080)},
+		{"t2.1", &v.I, 2.0, 2},
+		{"t2.2", &v.I, 0.9, nil},
+		{"t2.3", &v.U, -2.0, nil},
+		{"t2.4", &v.I8, 1e10, nil},
+		{"t2.5", &v.PI, 2.5, nil},
+		{"t3.1", &v.F32, 0.1, float32(0.1)},
+		{"t3.2", &v.F32, 1e40, nil},
+		{"t3.3", &v.F64, 3, 3.0},
+		{"t3.4", &v.Ratio, int64(1<<53 + 1), nil},
+	}
+	for _, test := range tests {
+		rv := reflect.ValueOf(test.ptr).Elem()
+		rv.SetZero()
+		err := Default(test.value).Transform(test.ptr)
+		if test.want == nil {
+			if ie, ok := err.(InternalError); assert.True(t, ok, test.tag) {
+				assert.Equal(t, ErrDefaultType, ie.InternalError(), test.tag)
+			}
+			assert.True(t, rv.IsZero(), test.tag)
+			continue
+		}
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, test.want, rv.Interface(), test.tag)
+	}
+}
+
+type defaultPort uint16
+
+func TestDefaultCopy(t *testing.T) {
+	type config struct {
+		Tags   []string
+		Labels map[string][]string
+		Hosts  [1][]string
+	}
+	tags := Default([]string{"a", "b"})
+	labels := Default(map[string][]string{"env": {"dev"}})
+	hosts := Default([1][]string{{"localhost"}})
+	var c1, c2 config
+	for _, c := range []*config{&c1, &c2} {
+		assert.NoError(t, ValidateStruct(c, Field(&c.Tags, tags), Field(&c.Labels, labels), Field(&c.Hosts, hosts)))
+	}
+	c1.Tags[0] = "x"
+	c1.Labels["env"][0] = "prod"
+	c1.Labels["new"] = nil
+	c1.Hosts[0][0] = "example.com"
+	assert.Equal(t, "a", c2.Tags[0])
+	assert.Equal(t, "dev", c2.Labels["env"][0])
+	assert.Equal(t, 1, len(c2.Labels))
+	assert.Equal(t, "localhost", c2.Hosts[0][0])
+
+	// typed defaults are copied as well
+	rules := Struct[config](FieldOf("tags", func(c *config) *[]string { return &c.Tags }, DefaultOf([]string{"a"})))
+	c1, c2 = config{}, config{}
+	assert.NoError(t, rules.Validate(&c1))
+	assert.NoError(t, rules.Validate(&c2))
+	c1.Tags[0] = "x"
+	assert.Equal(t, "a", c2.Tags[0])
+}
+
+func TestPlanDefaults(t *testing.T) {
+	ctx := context.WithValue(context.Background(), regionKey{}, "eu-west-1")
+	ctx, plan := PlanDefaults(ctx)
+	c := defaultConfig{Host: "example.com", Retries: 7, DB: &defaultDBConfig{}}
+	err := ValidateStructWithContext(ctx, &c, c.rules()...)
+	assert.EqualError(t, err, "Retries: must be no greater than 5.")
+
+	// no field is modified
+	assert.Equal(t, uint16(0), c.Port)
+	assert.Equal(t, "", c.Region)
+	assert.Nil(t, c.Timeout)
+	assert.Equal(t, "", c.DB.Driver)
+
+	defaults := plan.Defaults()
+	paths := make([]any, len(defaults))
+	values := make([]any, len(defaults))
+	for i, d := range defaults {
+		paths[i] = d.Path.String()
+		values[i] = d.Value
+	}
+	assert.Equal(t, []any{"Port", "Region", "Timeout", "DB.Driver", "DB.Pool"}, paths)
+	assert.Equal(t, []any{8080, "eu-west-1", 2.5, "postgres", 10}, values)
+
+	// typed rules
+	ctx, plan = PlanDefaults(context.Background())
+	rules := Struct[defaultConfig](FieldOf("retries", func(c *defaultConfig) *int { return &c.Retries }, DefaultOf(3), Max(2)))
+	c = defaultConfig{}
+	assert.EqualError(t, rules.ValidateWithContext(ctx, &c), "retries: must be no greater than 2.")
+	assert.Equal(t, 0, c.Retries)
+	assert.Equal(t, 1, len(plan.Defaults()))
+	assert.Equal(t, "/retries", plan.Defaults()[0].Path.Pointer())
+}
@@ -0,0 +1,340 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotDiscriminable is the error that the value being validated by a discriminated rule
+	// is neither a map, a struct nor a JSON object.
+	ErrNotDiscriminable = errors.New("only a map, a struct or a JSON object can be validated by a discriminated rule")
+
+	// ErrDiscriminatorField is the error that the discriminator field cannot be found in the struct being validated.
+	ErrDiscriminatorField = errors.New("the discriminator field cannot be found in the struct being validated")
+
+	// ErrDiscriminatorInvalid is the error returned when the discriminator value does not match any variant.
+	ErrDiscriminatorInvalid = NewError("validation_discriminator_invalid", "must be one of {{.values}}")
+
+	// ErrJSONObjectInvalid is the error returned when a JSON value cannot be decoded as expected.
+	ErrJSONObjectInvalid = NewError("validation_json_object_invalid", "must be a valid JSON object")
+
+	// ErrOneOfNone is the error returned when a value matches none of the rule sets of a OneOf rule,
+	// or none of the rules of an ExactlyOne rule.
+	ErrOneOfNone = NewError("validation_one_of_none", "must match one of the allowed schemas")
+
+	// ErrOneOfMultiple is the error returned when a value matches more than one of the rule sets of a OneOf rule,
+	// or more than one of the rules of an ExactlyOne rule.
+	ErrOneOfMultiple = NewError("validation_one_of_multiple", "must match exactly one of the allowed schemas")
+)
+
+type (
+	// DiscriminatedRule is a validation rule that validates a value using the rules of the variant
+	// selected by the value of a discriminator key or field.
+	DiscriminatedRule struct {
+		key      string
+		variants []discriminatedVariant
+		err      Error
+	}
+
+	discriminatedVariant struct {
+		value any
+		rules []Rule[any]
+	}
+
+	// OneOfRule is a validation rule that checks if a value passes exactly one of several rule sets.
+	OneOfRule struct {
+		sets [][]Rule[any]
+		none Error
+		many Error
+	}
+
+	decodeJSONRule[T any] struct {
+		rules []Rule[any]
+	}
+)
+
+// Discriminated returns a validation rule that reads the discriminator from the given key, and validates the value
+// using the rules of the variant registered for the discriminator value with Variant. For example,
+//
+//	kv.Discriminated("type").
+//	    Variant("card", kv.Map(kv.Key("type"), kv.Key("number", kv.Required))).
+//	    Variant("bank", kv.Map(kv.Key("type"), kv.Key("iban", kv.Required)))
+//
+// The value being validated can be a map with string keys, a struct, or a JSON object given as json.RawMessage.
+// The discriminator of a struct is read from the field whose error name or Go name is the key,
+// and a JSON object is decoded into a map[string]any before it is validated by the rules of the variant.
+// Discriminator values are compared in their fmt representation, so that "1" matches both 1 and 1.0.
+//
+// If the discriminator is missing, such as a struct field promoted through a nil embedded pointer,
+// ErrKeyMissing is reported for the key. If it does not match any variant,
+// ErrDiscriminatorInvalid is reported for the key, with the allowed values given as the "values" param.
+// A nil value is considered valid.
+func Discriminated(key string) DiscriminatedRule {
+	return DiscriminatedRule{key: key, err: ErrDiscriminatorInvalid}
+}
+
+// Variant registers the rules validating the values whose discriminator equals the given value.
+func (r DiscriminatedRule) Variant(value any, rules ...Rule[any]) DiscriminatedRule {
+	r.variants = append(r.variants[:len(r.variants):len(r.variants)], discriminatedVariant{value: value, rules: rules})
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r DiscriminatedRule) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r DiscriminatedRule) ValidateWithContext(ctx context.Context, value any) error {
+	if raw, ok := value.(json.RawMessage); ok {
+		if len(raw) == 0 || string(raw) == "null" {
+			return nil
+		}
+		var m map[string]any
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return ErrJSONObjectInvalid
+		}
+		value = m
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	var discriminator reflect.Value
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		if rv.IsNil() {
+			return nil
+		}
+		discriminator = rv.MapIndex(reflect.ValueOf(r.key).Convert(rv.Type().Key()))
+		if !discriminator.IsValid() {
+			return Errors{r.key: ErrKeyMissing}
+		}
+	case rv.Kind() == reflect.Struct:
+		var found bool
+		if discriminator, found = discriminatorField(rv, r.key); !found {
+			return NewInternalError(ErrDiscriminatorField)
+		}
+		if !discriminator.IsValid() {
+			return Errors{r.key: ErrKeyMissing}
+		}
+	case !rv.IsValid():
+		return nil
+	default:
+		return NewInternalError(ErrNotDiscriminable)
+	}
+
+	d, _ := Indirect(discriminator.Interface())
+	name := getErrorKeyName(d)
+	for _, v := range r.variants {
+		if getErrorKeyName(v.value) != name {
+			continue
+		}
+		if ctx == nil {
+			return Validate(value, v.rules...)
+		}
+		return validateWithContext(ctx, value, v.rules...)
+	}
+
+	values := make([]string, len(r.variants))
+	for i, v := range r.variants {
+		values[i] = getErrorKeyName(v.value)
+	}
+	return Errors{r.key: r.err.SetParams(map[string]any{"values": strings.Join(values, ", ")})}
+}
+
+// Error sets the error message returned when the discriminator value does not match any variant.
+func (r DiscriminatedRule) Error(message string) DiscriminatedRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct returned when the discriminator value does not match any variant.
+func (r DiscriminatedRule) ErrorObject(err Error) DiscriminatedRule {
+	r.err = err
+	return r
+}
+
+// discriminatorField returns the field of the struct whose error name or Go name is the key, and whether the struct
+// has such a field. The returned value is invalid if the field is promoted through a nil embedded pointer.
+func discriminatorField(rv reflect.Value, key string) (reflect.Value, bool) {
+	for _, f := range reflect.VisibleFields(rv.Type()) {
+		if !f.IsExported() || f.Anonymous && f.Type.Kind() == reflect.Struct {
+			continue
+		}
+		if getErrorFieldName(&f) == key || f.Name == key {
+			v, err := rv.FieldByIndexErr(f.Index)
+			if err != nil {
+				return reflect.Value{}, true
+			}
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// DecodeJSON returns a validation rule that decodes a JSON object into a value of type T,
+// and validates a pointer to the decoded value with the given rules. If T implements Validatable,
+// its Validate method is called as well. It is meant to dispatch the variants of a Discriminated rule
+// to struct validators:
+//
+//	kv.Discriminated("type").
+//	    Variant("card", kv.DecodeJSON[CardPayment]()).
+//	    Variant("bank", kv.DecodeJSON[BankPayment]())
+//
+// The value being validated can be a json.RawMessage, a byte slice, a string, or a map that is converted to JSON first.
+// If the value cannot be decoded into T, ErrJSONObjectInvalid is returned. A nil value is considered valid.
+func DecodeJSON[T any](rules ...Rule[any]) Rule[any] {
+	return decodeJSONRule[T]{rules: rules}
+}
+
+// Validate checks if the given value is valid or not.
+func (r decodeJSONRule[T]) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r decodeJSONRule[T]) ValidateWithContext(ctx context.Context, value any) error {
+	value, isNil := Indirect(value)
+	if isNil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return ErrJSONObjectInvalid
+		}
+	}
+
+	var t T
+	if err := json.Unmarshal(data, &t); err != nil {
+		return ErrJSONObjectInvalid
+	}
+	if ctx == nil {
+		return Validate(&t, r.rules...)
+	}
+	return validateWithContext(ctx, &t, r.rules...)
+}
+
+// OneOf returns a validation rule that checks if a value passes exactly one of the given rule sets.
+// It is meant for polymorphic values without an explicit discriminator. For example,
+//
+//	kv.OneOf(
+//	    []kv.Rule[any]{kv.Map(kv.Key("email", kv.Required, is.Email))},
+//	    []kv.Rule[any]{kv.Map(kv.Key("phone", kv.Required, is.E164))},
+//	)
+//
+// If the value passes none of the sets, ErrOneOfNone is returned. If it passes more than one,
+// ErrOneOfMultiple is returned with the indices of the passing sets given as the "matches" param.
+// An InternalError returned by any rule stops the validation and is returned immediately.
+func OneOf(sets ...[]Rule[any]) OneOfRule {
+	return OneOfRule{sets: sets, none: ErrOneOfNone, many: ErrOneOfMultiple}
+}
+
+// Validate checks if the given value is valid or not.
+func (r OneOfRule) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r OneOfRule) ValidateWithContext(ctx context.Context, value any) error {
+	return validateOneOf(len(r.sets), func(i int) error {
+		if ctx == nil {
+			return Validate(value, r.sets[i]...)
+		}
+		return validateWithContext(ctx, value, r.sets[i]...)
+	}, r.none, r.many)
+}
+
+// validateOneOf validates a value with n rule sets by calling validate with the index of each set,
+// and checks that the value passes exactly one of them. An InternalError stops the validation and is returned.
+func validateOneOf(n int, validate func(i int) error, none, many Error) error {
+	var matches []string
+	for i := 0; i < n; i++ {
+		err := validate(i)
+		if isInternalError(err) {
+			return err
+		}
+		if err == nil {
+			matches = append(matches, strconv.Itoa(i))
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return none
+	case 1:
+		return nil
+	}
+	return many.SetParams(map[string]any{"matches": strings.Join(matches, ", ")})
+}
+
+// Error sets the error message for the rule.
+func (r OneOfRule) Error(message string) OneOfRule {
+	r.none = r.none.SetMessage(message)
+	r.many = r.many.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r OneOfRule) ErrorObject(err Error) OneOfRule {
+	r.none = err
+	r.many = err
+	return r
+}
+
+func (r DiscriminatedRule) ruleLists() []namedRules {
+	lists := make([]namedRules, len(r.variants))
+	for i, v := range r.variants {
+		lists[i].rules = anyRules(v.rules)
+	}
+	return lists
+}
+
+func (r OneOfRule) ruleLists() []namedRules {
+	lists := make([]namedRules, len(r.sets))
+	for i, rules := range r.sets {
+		lists[i].rules = anyRules(rules)
+	}
+	return lists
+}
+
+func (r decodeJSONRule[T]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}}
+}
+
+func (r DiscriminatedRule) referencesFields() bool {
+	for _, v := range r.variants {
+		if rulesReferenceFields(v.rules) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r OneOfRule) referencesFields() bool {
+	for _, rules := range r.sets {
+		if rulesReferenceFields(rules) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type cardPayment struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+func (p cardPayment) Validate() error {
+	return ValidateStruct(&p, Field(&p.Number, Required, Length(16, 19)))
+}
+
+type bankPayment struct {
+	Type string `json:"type"`
+	IBAN string `json:"iban"`
+}
+
+type payment struct {
+	Kind   string `json:"kind"`
+	Number string
+	IBAN   string
+}
+
+type paymentBase struct {
+	Kind string `json:"kind"`
+}
+
+type embeddedPayment struct {
+	*paymentBase
+	Number string
+}
+
+func TestDiscriminated(t *testing.T) {
+	mapRule := Discriminated("type").
+		Variant("card", Map(Key("type"), Key("number", Required))).
+		Variant("bank", Map(Key("type"), Key("iban", Required)))
+	var m0 map[string]any
+	tests := []struct {
+		tag   string
+		rule  DiscriminatedRule
+		value any
+		err   string
+	}{
+		// maps
+		{"t1.1", mapRule, nil, ""},
+		{"t1.2", mapRule, m0, ""},
+		{"t1.3", mapRule, map[string]any{"type": "card", "number": "4111"}, ""},
+		{"t1.4", mapRule, map[string]any{"type": "card", "iban": "DE89"}, "iban: key not expected; number: required key is missing."},
+		{"t1.5", mapRule, map[string]any{"type": "bank", "iban": "DE89"}, ""},
+		{"t1.6", mapRule, map[string]any{"type": "cash"}, "type: must be one of card, bank."},
+		{"t1.7", mapRule, map[string]any{"number": "4111"}, "type: required key is missing."},
+		{"t1.8", mapRule, &map[string]any{"type": "bank", "iban": ""}, "iban: cannot be blank."},
+		// JSON objects
+		{"t2.1", mapRule, json.RawMessage(`{"type":"card","number":"4111"}`), ""},
+		{"t2.2", mapRule, json.RawMessage(`{"type":"bank"}`), "iban: required key is missing."},
+		{"t2.3", mapRule, json.RawMessage(`[1, 2]`), "must be a valid JSON object"},
+		{"t2.4", mapRule, json.RawMessage(`null`), ""},
+		{"t2.5", Discriminated("version").Variant(1, Map(Key("version"))), json.RawMessage(`{"version":1}`), ""},
+		// structs
+		{"t3.1", Discriminated("kind").Variant("card", By(requireNumber)), &payment{Kind: "card", Number: "4111"}, ""},
+		{"t3.2", Discriminated("kind").Variant("card", By(requireNumber)), &payment{Kind: "card"}, "number is required"},
+		{"t3.3", Discriminated("Kind").Variant("card"), payment{Kind: "bank"}, "Kind: must be one of card."},
+		{"t3.4", Discriminated("kind").Variant("card"), (*payment)(nil), ""},
+		{"t3.5", Discriminated("kind").Variant("card"), embeddedPayment{paymentBase: &paymentBase{Kind: "card"}}, ""},
+		{"t3.6", Discriminated("kind").Variant("card"), embeddedPayment{Number: "4111"}, "kind: required key is missing."},
+		// custom errors
+		{"t4.1", mapRule.Error("unknown type, use {{.values}}"), map[string]any{"type": "cash"}, "type: unknown type, use card, bank."},
+		{"t4.2", mapRule.ErrorObject(NewError("code", "bad type")), map[string]any{"type": "cash"}, "type: bad type."},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, test.rule.Validate(test.value), test.tag)
+	}
+
+	var err error
+	err = Discriminated("kind").Validate(123)
+	assert.Equal(t, ErrNotDiscriminable, err.(InternalError).InternalError())
+	err = Discriminated("missing").Validate(payment{})
+	assert.Equal(t, ErrDiscriminatorField, err.(InternalError).InternalError())
+
+	err = mapRule.Validate(map[string]any{"type": "cash"})
+	assert.Equal(t, "validation_discriminator_invalid", err.(Errors)["type"].(Error).Code())
+}
+
+func requireNumber(value any) error {
+	if value.(*payment).Number == "" {
+		return errors.New("number is required")
+	}
+	return nil
+}
+
+func TestDecodeJSON(t *testing.T) {
+	rule := Discriminated("type").
+		Variant("card", DecodeJSON[cardPayment]()).
+		Variant("bank", DecodeJSON[bankPayment](By(func(value any) error {
+			if value.(*bankPayment).IBAN == "" {
+				return errors.New("iban is required")
+			}
+			return nil
+		})))
+	tests := []struct {
+		tag   string
+		value any
+		err   string
+	}{
+		{"t1", json.RawMessage(`{"type":"card","number":"4111111111111111"}`), ""},
+		{"t2", json.RawMessage(`{"type":"card","number":"4111"}`), "number: the length must be between 16 and 19."},
+		{"t3", json.RawMessage(`{"type":"bank"}`), "iban is required"},
+		{"t4", map[string]any{"type": "card", "number": ""}, "number: cannot be blank."},
+		{"t5", map[string]any{"type": "card", "number": 123}, "must be a valid JSON object"},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+	}
+
+	assertError(t, "", DecodeJSON[cardPayment]().Validate(nil), "nil")
+	assertError(t, "number: cannot be blank.", DecodeJSON[cardPayment]().Validate(`{}`), "string")
+	assertError(t, "must be a valid JSON object", DecodeJSON[cardPayment]().Validate([]byte(`{`)), "bytes")
+}
+
+func TestOneOf(t *testing.T) {
+	rule := OneOf(
+		[]Rule[any]{Map(Key("email", Required)).AllowExtraKeys()},
+		[]Rule[any]{Map(Key("phone", Required)).AllowExtraKeys()},
+	)
+	tests := []struct {
+		tag   string
+		value any
+		err   string
+	}{
+		{"t1", map[string]any{"email": "a@b.c"}, ""},
+		{"t2", map[string]any{"phone": "123"}, ""},
+		{"t3", map[string]any{"name": "a"}, "must match one of the allowed schemas"},
+		{"t4", map[string]any{"email": "a@b.c", "phone": "123"}, "must match exactly one of the allowed schemas"},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+	}
+
+	err := rule.Validate(map[string]any{"email": "a@b.c", "phone": "123"})
+	assert.Equal(t, "0, 1", err.(Error).Params()["matches"])
+
+	assert.EqualError(t, rule.Error("ambiguous").Validate(map[string]any{}), "ambiguous")
+	assert.EqualError(t, rule.ErrorObject(NewError("code", "abc")).Validate(map[string]any{}), "abc")
+
+	// internal errors
+	err = OneOf([]Rule[any]{Required}, []Rule[any]{&validateInternalError{}}).Validate("internal")
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+
+	// with context
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	ctxRule := WithContext(func(ctx context.Context, value any) error {
+		if value != ctx.Value(contains) {
+			return errors.New("unexpected value")
+		}
+		return nil
+	})
+	assert.NoError(t, OneOf([]Rule[any]{ctxRule}, []Rule[any]{In("xyz")}).ValidateWithContext(ctx, "abc"))
+}
@@ -42,8 +42,10 @@ func (r EachRule) ValidateWithContext(ctx context.Context, value any) error {
 			var err error
 			if ctx == nil {
 				err = Validate(val, r.rules...)
+			} else if tracksPath(ctx) {
+				err = validateWithContext(withPathElement(ctx, r.getString(k)), val, r.rules...)
 			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
+				err = validateWithContext(ctx, val, r.rules...)
 			}
 			if err != nil {
 				errs[r.getString(k)] = err
@@ -55,8 +57,10 @@ func (r EachRule) ValidateWithContext(ctx context.Context, value any) error {
 			var err error
 			if ctx == nil {
 				err = Validate(val, r.rules...)
+			} else if tracksPath(ctx) {
+				err = validateWithContext(withPathElement(ctx, strconv.Itoa(i)), val, r.rules...)
 			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
+				err = validateWithContext(ctx, val, r.rules...)
 			}
 			if err != nil {
 				errs[strconv.Itoa(i)] = err
@@ -90,8 +94,16 @@ func (r EachRule) getString(value reflect.Value) string {
 		if value.IsNil() {
 			return ""
 		}
-		return value.Elem().String()
+		return getErrorKeyName(value.Elem().Interface())
 	default:
-		return value.String()
+		return getErrorKeyName(value.Interface())
 	}
 }
+
+func (r EachRule) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}}
+}
+
+func (r EachRule) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
@@ -32,6 +32,8 @@ func TestEach(t *testing.T) {
 		{"t12", []any{struct{ foo string }{"foo"}}, ""},
 		{"t13", []any{nil, a}, "0: cannot be blank; 1: cannot be blank."},
 		{"t14", []any{c0, c1, f}, "0: cannot be blank."},
+		{"t15", map[int]string{1: "", 2: "value2", 10: ""}, "1: cannot be blank; 10: cannot be blank."},
+		{"t16", map[any]string{true: "", 1.5: ""}, "1.5: cannot be blank; true: cannot be blank."},
 	}
 
 	for _, test := range tests {
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -31,12 +32,25 @@ type (
 		code    string
 		message string
 		params  map[string]any
+		// custom tells if the message was set by SetMessage, such as by the Error method of a rule,
+		// so that it is not replaced by a translation.
+		custom bool
 	}
 
 	// Errors represents the validation errors that are indexed by struct field names, map or slice keys.
 	// values are Error or Errors (for map, slice and array error value is Errors).
 	Errors map[string]error
 
+	// ErrorList represents the errors of all failing rules of a single value, as collected by ValidateAll.
+	ErrorList []error
+
+	// KeyError represents the error of an invalid map key, as reported by EachEntry and EachSeq2.
+	// It is indexed in Errors by the name of its entry, like the error of the value of the entry,
+	// and both errors are merged into an ErrorList if the key and the value are invalid.
+	KeyError struct {
+		Err error
+	}
+
 	// InternalError represents an error that should NOT be treated as a validation error.
 	InternalError interface {
 		error
@@ -58,6 +72,11 @@ func (e internalError) InternalError() error {
 	return e.error
 }
 
+// Unwrap returns the actual error that it wraps around.
+func (e internalError) Unwrap() error {
+	return e.error
+}
+
 // SetCode set the error's translation code.
 func (e ErrorObject) SetCode(code string) Error {
 	e.code = code
@@ -90,9 +109,10 @@ func (e ErrorObject) Params() map[string]any {
 	return e.params
 }
 
-// SetMessage set the error's message.
+// SetMessage set the error's message. The message is kept as it is when the error is translated.
 func (e ErrorObject) SetMessage(message string) Error {
 	e.message = message
+	e.custom = true
 	return e
 }
 
@@ -101,6 +121,55 @@ func (e ErrorObject) Message() string {
 	return e.message
 }
 
+// MarshalJSON converts the error into a JSON object holding its code, message and params.
+func (e ErrorObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{Code: e.code, Message: e.Error(), Params: e.params})
+}
+
+// UnmarshalJSON restores the error from a JSON object holding its code, message and params,
+// or from a JSON string holding its message. Numeric params are restored as float64.
+func (e *ErrorObject) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	eo, ok := errorObjectFromJSON(v)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into an error", data)
+	}
+	*e = eo
+	return nil
+}
+
+// MarshalText returns the error message.
+func (e ErrorObject) MarshalText() ([]byte, error) {
+	return []byte(e.Error()), nil
+}
+
+// UnmarshalText restores the error from its message.
+func (e *ErrorObject) UnmarshalText(text []byte) error {
+	*e = ErrorObject{message: string(text)}
+	return nil
+}
+
+// Is reports whether the error matches the target, so that errors.Is(err, kv.ErrRequired) works
+// regardless of the message and params of err. Errors are matched by code. If the error has
+// no code, they are matched by message instead.
+func (e ErrorObject) Is(target error) bool {
+	t, ok := target.(Error)
+	if !ok {
+		return false
+	}
+	if e.code != "" || t.Code() != "" {
+		return e.code == t.Code()
+	}
+	return e.message == t.Message()
+}
+
+func (e ErrorObject) customMessage() bool {
+	return e.custom
+}
+
 // Error returns the error message.
 func (e ErrorObject) Error() string {
 	if len(e.params) == 0 {
@@ -142,17 +211,27 @@ func (es Errors) Error() string {
 	return s.String()
 }
 
-// MarshalJSON converts the Errors into a valid JSON.
-func (es Errors) MarshalJSON() ([]byte, error) {
-	errs := map[string]any{}
+// Unwrap returns the errors of the fields sorted by field name, so that errors.Is and errors.As
+// can reach the nested errors.
+func (es Errors) Unwrap() []error {
+	keys := make([]string, 0, len(es))
 	for key, err := range es {
-		if ms, ok := err.(json.Marshaler); ok {
-			errs[key] = ms
-		} else {
-			errs[key] = err.Error()
+		if err != nil {
+			keys = append(keys, key)
 		}
 	}
-	return json.Marshal(errs)
+	sort.Strings(keys)
+
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		errs[i] = es[key]
+	}
+	return errs
+}
+
+// MarshalJSON converts the Errors into a valid JSON in the format selected by ErrorsJSONFormat.
+func (es Errors) MarshalJSON() ([]byte, error) {
+	return es.MarshalJSONFormat(ErrorsJSONFormat)
 }
 
 // Filter removes all nils from Errors and returns back the updated Errors as an error.
@@ -169,6 +248,48 @@ func (es Errors) Filter() error {
 	return es
 }
 
+// Error returns the error string of KeyError, which is the error string of the key prefixed with "key: ".
+func (e KeyError) Error() string {
+	return "key: " + e.Err.Error()
+}
+
+// Unwrap returns the error of the key.
+func (e KeyError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns the error string of ErrorList.
+func (es ErrorList) Error() string {
+	var s strings.Builder
+	for i, err := range es {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(err.Error())
+	}
+	return s.String()
+}
+
+// Unwrap returns the errors of the list, so that errors.Is and errors.As can reach them.
+func (es ErrorList) Unwrap() []error {
+	return slices.DeleteFunc(slices.Clone(es), func(err error) bool { return err == nil })
+}
+
+// MarshalJSON converts the ErrorList into a JSON array in the format selected by ErrorsJSONFormat.
+func (es ErrorList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonValue(es, ErrorsJSONFormat))
+}
+
+// Filter removes all nils from ErrorList and returns back the updated ErrorList as an error.
+// If the length of ErrorList becomes 0, it will return nil.
+func (es ErrorList) Filter() error {
+	es = slices.DeleteFunc(es, func(err error) bool { return err == nil })
+	if len(es) == 0 {
+		return nil
+	}
+	return es
+}
+
 // NewError create new validation error.
 func NewError(code, message string) Error {
 	return ErrorObject{
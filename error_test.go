@@ -70,6 +70,33 @@ func TestErrors_Filter(t *testing.T) {
 	assert.Nil(t, errs.Filter())
 }
 
+func TestErrorList_Error(t *testing.T) {
+	errs := ErrorList{errors.New("A1"), errors.New("A2")}
+	assert.Equal(t, "A1, A2", errs.Error())
+	assert.Equal(t, "A: A1, A2.", Errors{"A": errs}.Error())
+	assert.Equal(t, "", ErrorList{}.Error())
+}
+
+func TestErrorList_MarshalJSON(t *testing.T) {
+	errs := Errors{
+		"A": ErrorList{errors.New("A1"), NewError("code", "A2")},
+		"B": ErrorList{Errors{"2": errors.New("B1")}},
+	}
+	errsJSON, err := errs.MarshalJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"A":["A1","A2"],"B":[{"2":"B1"}]}`, string(errsJSON))
+}
+
+func TestErrorList_Filter(t *testing.T) {
+	errs := ErrorList{errors.New("A1"), nil, errors.New("A2")}
+	err := errs.Filter()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "A1, A2", err.Error())
+	}
+	assert.Nil(t, ErrorList{}.Filter())
+	assert.Nil(t, ErrorList{nil, nil}.Filter())
+}
+
 func TestErrorObject_SetCode(t *testing.T) {
 	err := NewError("A", "msg").(ErrorObject)
 
@@ -185,3 +212,52 @@ func TestValidationError(t *testing.T) {
 
 	assert.Equal(t, err.Params(), params)
 }
+
+func TestErrorObject_Is(t *testing.T) {
+	assert.True(t, errors.Is(ErrRequired, ErrRequired))
+	assert.True(t, errors.Is(ErrRequired.SetMessage("is required"), ErrRequired))
+	assert.True(t, errors.Is(ErrLengthOutOfRange.AddParam("min", 1), ErrLengthOutOfRange))
+	assert.True(t, errors.Is(Length(5, 10).Validate("abc"), ErrLengthOutOfRange))
+	assert.False(t, errors.Is(ErrRequired, ErrNotNilRequired))
+	assert.False(t, errors.Is(ErrRequired, errors.New("cannot be blank")))
+
+	// errors without a code are matched by message
+	assert.True(t, errors.Is(NewError("", "abc"), NewError("", "abc")))
+	assert.False(t, errors.Is(NewError("", "abc"), NewError("", "xyz")))
+	assert.False(t, errors.Is(NewError("", "abc"), NewError("code", "abc")))
+}
+
+func TestErrors_Unwrap(t *testing.T) {
+	errs := Errors{
+		"B": ErrRequired,
+		"C": nil,
+		"A": Errors{"2": ErrorList{errors.New("A1"), ErrLengthTooLong.AddParam("max", 3)}},
+	}
+	unwrapped := errs.Unwrap()
+	if assert.Equal(t, 2, len(unwrapped)) {
+		_, ok := unwrapped[0].(Errors)
+		assert.True(t, ok)
+		assert.Equal(t, ErrRequired, unwrapped[1])
+	}
+
+	assert.True(t, errors.Is(errs, ErrRequired))
+	assert.True(t, errors.Is(errs, ErrLengthTooLong))
+	assert.False(t, errors.Is(errs, ErrNotNilRequired))
+
+	var e ErrorObject
+	if assert.True(t, errors.As(errs["A"], &e)) {
+		assert.Equal(t, "the length must be no more than 3", e.Error())
+	}
+	var list ErrorList
+	assert.True(t, errors.As(errs, &list))
+
+	assert.Equal(t, 0, len(ErrorList{nil}.Unwrap()))
+}
+
+func TestInternalError_Unwrap(t *testing.T) {
+	cause := errors.New("abc")
+	err := NewInternalError(cause)
+	assert.Equal(t, cause, errors.Unwrap(err))
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, errors.Is(NewInternalError(Errors{"a": ErrRequired}), ErrRequired))
+}
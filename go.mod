@@ -2,4 +2,7 @@ module github.com/khatibomar/kv
 
 go 1.23
 
-require github.com/asaskevich/govalidator v0.0.0-20200108200545-475eaeb16496
+require (
+	github.com/asaskevich/govalidator v0.0.0-20200108200545-475eaeb16496
+	golang.org/x/text v0.21.0
+)
@@ -0,0 +1,136 @@
+// Package httpkv decodes and validates the payloads of HTTP requests.
+//
+// Decode reads a value from the JSON or form-urlencoded body of a request, or from its query string,
+// and validates it with the request context. Handler wraps a function receiving the decoded value
+// into an http.Handler that responds with RFC 7807 problem details when the request is invalid.
+package httpkv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/khatibomar/kv"
+)
+
+var (
+	// ErrUnsupportedMediaType is the error that the content type of a request body cannot be decoded.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// MaxBodySize is the maximum number of bytes of a request body read by Decode. If the body is larger,
+	// a *DecodeError wrapping an *http.MaxBytesError is returned. A value of zero or less disables the limit.
+	MaxBodySize int64 = 1 << 20
+)
+
+type (
+	// DecodeError is the error that the payload of a request is malformed.
+	DecodeError struct {
+		Err error
+	}
+
+	// ValidationError is the error that the decoded payload of a request is invalid.
+	// Err is the validation error, such as a kv.Errors.
+	ValidationError struct {
+		Err error
+	}
+)
+
+// Error returns the error string of DecodeError.
+func (e *DecodeError) Error() string {
+	return "invalid request payload: " + e.Err.Error()
+}
+
+// Unwrap returns the error that caused the payload to be rejected.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns the error string of ValidationError.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the validation error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Decode decodes a value of type T from the request and validates it.
+//
+// The value is decoded from the query string of requests without a body, such as GET requests.
+// Otherwise it is decoded from the body according to its content type:
+//   - "application/json" or any "+json" type is decoded by encoding/json.
+//   - "application/x-www-form-urlencoded" is decoded together with the query string.
+//     The values of the body take precedence over those of the query string.
+//
+// Query strings and forms can only be decoded into structs. A struct field is matched by its "form" tag,
+// then by its "json" tag, then by its name. Fields of basic types, pointers and slices of them, and types
+// implementing encoding.TextUnmarshaler are supported. The body is limited to MaxBodySize bytes, and a JSON body
+// must hold a single value.
+//
+// The decoded value is validated by kv.ValidateWithContext with the request context, so that types
+// implementing kv.Validatable or kv.ValidatableWithContext are validated, and the errors are translated
+// into the locale carried by the context.
+//
+// A *DecodeError is returned if the payload is malformed, an error wrapping ErrUnsupportedMediaType if the
+// content type is not supported, and a *ValidationError if the value is invalid. A kv.InternalError raised
+// during the validation is returned as it is.
+func Decode[T any](r *http.Request) (T, error) {
+	var v T
+	if err := decode(r, &v); err != nil {
+		return v, err
+	}
+	if err := kv.ValidateWithContext(r.Context(), &v); err != nil {
+		if ie, ok := err.(kv.InternalError); ok && ie.InternalError() != nil {
+			return v, err
+		}
+		return v, &ValidationError{Err: err}
+	}
+	return v, nil
+}
+
+// decode decodes the payload of the request into the value pointed to by ptr.
+func decode(r *http.Request, ptr any) error {
+	if r.Body == nil || r.Body == http.NoBody || r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return decodeForm(r.URL.Query(), ptr)
+	}
+
+	ct := r.Header.Get("Content-Type")
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil && ct != "" {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, ct)
+	}
+	if MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+	}
+	switch {
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(ptr); err != nil {
+			if err == io.EOF {
+				err = errors.New("empty body")
+			}
+			return &DecodeError{Err: err}
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			var mbe *http.MaxBytesError
+			if !errors.As(err, &mbe) {
+				err = errors.New("unexpected data after the JSON value")
+			}
+			return &DecodeError{Err: err}
+		}
+		return nil
+	case mt == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return &DecodeError{Err: err}
+		}
+		return decodeForm(r.Form, ptr)
+	case mt == "" && r.ContentLength == 0:
+		return decodeForm(r.URL.Query(), ptr)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mt)
+}
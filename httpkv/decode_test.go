@@ -0,0 +1,137 @@
+package httpkv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv"
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type user struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+func (u *user) ValidateWithContext(ctx context.Context) error {
+	if u.Email == "internal" {
+		return kv.NewInternalError(errors.New("lookup failed"))
+	}
+	return kv.ValidateStructWithContext(ctx, u,
+		kv.Field(&u.Name, kv.Required, kv.Length(2, 20)),
+		kv.Field(&u.Age, kv.Any(kv.Min(18))),
+	)
+}
+
+func newRequest(method, target, contentType, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body == "" {
+		r = httptest.NewRequest(method, target, nil)
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		tag         string
+		method      string
+		target      string
+		contentType string
+		body        string
+		name        string
+		age         int
+		err         string
+	}{
+		{"t1", "POST", "/", "application/json", `{"name":"Ann","age":20}`, "Ann", 20, ""},
+		{"t2", "POST", "/", "application/json; charset=utf-8", `{"name":"Ann","age":20}`, "Ann", 20, ""},
+		{"t3", "POST", "/", "application/merge-patch+json", `{"name":"Ann","age":20}`, "Ann", 20, ""},
+		{"t4", "POST", "/?age=30", "application/x-www-form-urlencoded", "name=Ann&age=20", "Ann", 20, ""},
+		{"t5", "POST", "/?age=30", "application/x-www-form-urlencoded", "name=Ann", "Ann", 30, ""},
+		{"t6", "GET", "/?name=Ann&age=20", "", "", "Ann", 20, ""},
+		{"t7", "POST", "/?name=Ann&age=20", "", "", "Ann", 20, ""},
+		{"t8", "POST", "/", "application/json", `{"name":"Ann","age":10}`, "Ann", 10, "age: must be no less than 18."},
+		{"t9", "POST", "/", "application/json", `{"name":`, "", 0, "invalid request payload: unexpected EOF"},
+		{"t10", "POST", "/", "application/json", ` `, "", 0, "invalid request payload: empty body"},
+		{"t11", "POST", "/", "text/plain", "Ann", "", 0, "unsupported media type: text/plain"},
+		{"t12", "POST", "/", "text/", "Ann", "", 0, "unsupported media type: text/"},
+		{"t13", "GET", "/?name=Ann&age=x", "", "", "Ann", 0, "invalid request payload: age: must be an integer"},
+		{"t14", "POST", "/", "application/json", `{"name":"Ann","age":20,"email":"internal"}`, "Ann", 20, "lookup failed"},
+	}
+	for _, test := range tests {
+		u, err := Decode[user](newRequest(test.method, test.target, test.contentType, test.body))
+		assert.Equal(t, test.name, u.Name, test.tag)
+		assert.Equal(t, test.age, u.Age, test.tag)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, test.err, test.tag)
+		}
+	}
+
+	// error types
+	_, err := Decode[user](newRequest("POST", "/", "application/json", `{"age":10}`))
+	var ve *ValidationError
+	if assert.True(t, errors.As(err, &ve)) {
+		_, ok := ve.Err.(kv.Errors)
+		assert.True(t, ok)
+	}
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `[]`))
+	var de *DecodeError
+	assert.True(t, errors.As(err, &de))
+	_, err = Decode[user](newRequest("POST", "/", "application/xml", `<user/>`))
+	assert.True(t, errors.Is(err, ErrUnsupportedMediaType))
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"email":"internal"}`))
+	_, ok := err.(kv.InternalError)
+	assert.True(t, ok)
+
+	// the request context is used
+	r := newRequest("POST", "/", "application/json", `{"name":"A","age":20}`)
+	r = r.WithContext(kv.WithLocale(r.Context(), "fr"))
+	_, err = Decode[user](r)
+	assert.EqualError(t, err, "name: la longueur doit être comprise entre 2 et 20.")
+
+	// trailing data
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann","age":20} {"name":"Bob"}`))
+	assert.EqualError(t, err, "invalid request payload: unexpected data after the JSON value")
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann","age":20}}`))
+	assert.EqualError(t, err, "invalid request payload: unexpected data after the JSON value")
+	_, err = Decode[user](newRequest("POST", "/", "application/json", "{\"name\":\"Ann\",\"age\":20}\n"))
+	assert.NoError(t, err)
+
+	// non-struct values can be decoded from JSON
+	n, err := Decode[[]int](newRequest("POST", "/", "application/json", `[1,2]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(n))
+	_, err = Decode[[]int](newRequest("GET", "/", "", ""))
+	assert.EqualError(t, err, "invalid request payload: cannot decode form values into []int")
+}
+
+func TestDecodeMaxBodySize(t *testing.T) {
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 30
+
+	var mbe *http.MaxBytesError
+	_, err := Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann","age":20,"email":"ann@example.com"}`))
+	assert.True(t, errors.As(err, &mbe))
+	var de *DecodeError
+	assert.True(t, errors.As(err, &de))
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann"}`+strings.Repeat(" ", 30)))
+	assert.True(t, errors.As(err, &mbe))
+	_, err = Decode[user](newRequest("POST", "/", "application/x-www-form-urlencoded", "name=Ann&age=20&email=ann%40example.com"))
+	assert.True(t, errors.As(err, &mbe))
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann","age":20}`))
+	assert.NoError(t, err)
+
+	// no limit
+	MaxBodySize = 0
+	_, err = Decode[user](newRequest("POST", "/", "application/json", `{"name":"Ann","age":20,"email":"ann@example.com"}`))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,179 @@
+package httpkv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// decodeForm decodes form values into the struct pointed to by ptr.
+func decodeForm(values url.Values, ptr any) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return &DecodeError{Err: fmt.Errorf("cannot decode form values into %v", rv.Type())}
+	}
+	return decodeStruct(values, rv)
+}
+
+// decodeStruct decodes form values into the fields of a struct.
+func decodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, ok := formFieldName(sf)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if sf.Anonymous && name == sf.Name && indirectType(sf.Type).Kind() == reflect.Struct && !implementsTextUnmarshaler(sf.Type) {
+			// promote the fields of an embedded struct
+			if fv.Kind() == reflect.Ptr {
+				if !sf.IsExported() {
+					continue
+				}
+				if fv.IsNil() {
+					fv.Set(reflect.New(sf.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := decodeStruct(values, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		vs, ok := values[name]
+		if !ok || len(vs) == 0 {
+			continue
+		}
+		if err := setField(fv, vs); err != nil {
+			return &DecodeError{Err: fmt.Errorf("%s: %w", name, err)}
+		}
+	}
+	return nil
+}
+
+// formFieldName returns the name of the form values of a struct field.
+// It returns false if the field should not be decoded.
+func formFieldName(sf reflect.StructField) (string, bool) {
+	for _, key := range []string{"form", "json"} {
+		if tag, ok := sf.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				return "", false
+			}
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+	return sf.Name, true
+}
+
+// setField sets a field from its form values.
+func setField(fv reflect.Value, vs []string) error {
+	if implementsTextUnmarshaler(fv.Type()) {
+		return setValue(fv, vs[0])
+	}
+	switch fv.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(fv.Type(), len(vs), len(vs))
+		for i, v := range vs {
+			if err := setValue(s.Index(i), v); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+		return nil
+	case reflect.Array:
+		if len(vs) > fv.Len() {
+			return fmt.Errorf("too many values, expected at most %d", fv.Len())
+		}
+		for i, v := range vs {
+			if err := setValue(fv.Index(i), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return setValue(fv, vs[0])
+}
+
+// setValue sets a value from a single form value.
+func setValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		if !fv.Type().Implements(textUnmarshalerType) {
+			return setValue(fv.Elem(), s)
+		}
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if fv.Type().Implements(textUnmarshalerType) {
+		return fv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		if s == "on" {
+			// the value sent by a checked checkbox without a value attribute
+			s = "true"
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be a non-negative integer")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("cannot decode a form value into %v", fv.Type())
+	}
+	return nil
+}
+
+func implementsTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,105 @@
+package httpkv
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type (
+	formBase struct {
+		ID uint `form:"id"`
+	}
+
+	formModel struct {
+		formBase
+		*Extra
+		Name     string `form:"name" json:"full_name"`
+		Email    string `json:"email,omitempty"`
+		Age      int8
+		Score    float64
+		Active   bool
+		Tags     []string  `form:"tag"`
+		Codes    [2]int    `form:"code"`
+		Nickname *string   `form:"nickname"`
+		Born     time.Time `form:"born"`
+		Until    *time.Time
+		Ignored  string `form:"-"`
+		hidden   string
+	}
+
+	Extra struct {
+		Note string `form:"note"`
+	}
+)
+
+func TestDecodeForm(t *testing.T) {
+	values := url.Values{
+		"id":        {"7"},
+		"note":      {"hi"},
+		"name":      {"Ann"},
+		"full_name": {"Ann Smith"},
+		"email":     {"a@example.com"},
+		"Age":       {"20"},
+		"Score":     {"1.5"},
+		"Active":    {"on"},
+		"tag":       {"a", "b"},
+		"code":      {"1", "2"},
+		"nickname":  {"annie"},
+		"born":      {"2000-01-02T00:00:00Z"},
+		"Until":     {"2030-01-02T00:00:00Z"},
+		"Ignored":   {"x"},
+		"hidden":    {"x"},
+	}
+	var m formModel
+	assert.NoError(t, decodeForm(values, &m))
+	assert.Equal(t, uint(7), m.ID)
+	if assert.NotNil(t, m.Extra) {
+		assert.Equal(t, "hi", m.Note)
+	}
+	assert.Equal(t, "Ann", m.Name)
+	assert.Equal(t, "a@example.com", m.Email)
+	assert.Equal(t, int8(20), m.Age)
+	assert.Equal(t, 1.5, m.Score)
+	assert.True(t, m.Active)
+	assert.Equal(t, "a,b", m.Tags[0]+","+m.Tags[1])
+	assert.Equal(t, [2]int{1, 2}, m.Codes)
+	if assert.NotNil(t, m.Nickname) {
+		assert.Equal(t, "annie", *m.Nickname)
+	}
+	assert.Equal(t, 2000, m.Born.Year())
+	if assert.NotNil(t, m.Until) {
+		assert.Equal(t, 2030, m.Until.Year())
+	}
+	assert.Equal(t, "", m.Ignored)
+	assert.Equal(t, "", m.hidden)
+
+	// decoding into a pointer
+	var p *formModel
+	assert.NoError(t, decodeForm(url.Values{"name": {"Ann"}}, &p))
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "Ann", p.Name)
+	}
+
+	tests := []struct {
+		tag    string
+		values url.Values
+		err    string
+	}{
+		{"t1", url.Values{"Age": {"300"}}, "invalid request payload: Age: must be an integer"},
+		{"t2", url.Values{"id": {"-1"}}, "invalid request payload: id: must be a non-negative integer"},
+		{"t3", url.Values{"Score": {"x"}}, "invalid request payload: Score: must be a number"},
+		{"t4", url.Values{"Active": {"x"}}, "invalid request payload: Active: must be a boolean"},
+		{"t5", url.Values{"code": {"1", "2", "3"}}, "invalid request payload: code: too many values, expected at most 2"},
+		{"t6", url.Values{"born": {"x"}}, `invalid request payload: born: parsing time "x" as "2006-01-02T15:04:05Z07:00": cannot parse "x" as "2006"`},
+	}
+	for _, test := range tests {
+		var m formModel
+		assert.EqualError(t, decodeForm(test.values, &m), test.err, test.tag)
+	}
+
+	var unsupported struct{ C chan int }
+	assert.EqualError(t, decodeForm(url.Values{"C": {"x"}}, &unsupported), "invalid request payload: C: cannot decode a form value into chan int")
+}
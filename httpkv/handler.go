@@ -0,0 +1,59 @@
+package httpkv
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/khatibomar/kv/problem"
+)
+
+// Handler returns an http.Handler that decodes and validates the request payload with Decode,
+// and calls the given function with the decoded value. If the payload cannot be decoded or is invalid,
+// the function is not called and the error is written by WriteError. For example,
+//
+//	mux.Handle("POST /users", httpkv.Handler(func(w http.ResponseWriter, r *http.Request, u User) {
+//	    // u is valid
+//	}))
+func Handler[T any](h func(w http.ResponseWriter, r *http.Request, v T)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, err := Decode[T](r)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		h(w, r, v)
+	})
+}
+
+// WriteError writes the given error returned by Decode as RFC 7807 problem details.
+//
+// A *ValidationError is written with the status 422 and the list of violations, a *DecodeError with the
+// status 400, or 413 if the body is larger than MaxBodySize, and an error wrapping ErrUnsupportedMediaType
+// with the status 415. Any other error is treated as an internal error and written with the status 500.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		ve  *ValidationError
+		de  *DecodeError
+		mbe *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &ve):
+		problem.Write(w, r, ve.Err)
+	case errors.As(err, &mbe):
+		newProblem(http.StatusRequestEntityTooLarge, err.Error()).ServeHTTP(w, r)
+	case errors.As(err, &de):
+		newProblem(http.StatusBadRequest, de.Error()).ServeHTTP(w, r)
+	case errors.Is(err, ErrUnsupportedMediaType):
+		newProblem(http.StatusUnsupportedMediaType, err.Error()).ServeHTTP(w, r)
+	default:
+		newProblem(http.StatusInternalServerError, "").ServeHTTP(w, r)
+	}
+}
+
+func newProblem(status int, detail string) *problem.Details {
+	return &problem.Details{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
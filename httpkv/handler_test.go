@@ -0,0 +1,63 @@
+package httpkv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+	"github.com/khatibomar/kv/problem"
+)
+
+func TestHandler(t *testing.T) {
+	h := Handler(func(w http.ResponseWriter, r *http.Request, u user) {
+		_, _ = w.Write([]byte("hello " + u.Name))
+	})
+
+	tests := []struct {
+		tag         string
+		contentType string
+		body        string
+		status      int
+		response    string
+	}{
+		{"t1", "application/json", `{"name":"Ann","age":20}`, http.StatusOK, "hello Ann"},
+		{"t2", "application/json", `{"name":"Ann","age":10}`, http.StatusUnprocessableEntity,
+			`{"title":"Validation Failed","status":422,"detail":"The request contains invalid values.","errors":[` +
+				`{"pointer":"/age","field":"age","code":"validation_min_greater_equal_than_required","message":"must be no less than 18","params":{"threshold":18}}]}` + "\n"},
+		{"t3", "application/json", `{"name":`, http.StatusBadRequest,
+			`{"title":"Bad Request","status":400,"detail":"invalid request payload: unexpected EOF"}` + "\n"},
+		{"t4", "text/plain", "Ann", http.StatusUnsupportedMediaType,
+			`{"title":"Unsupported Media Type","status":415,"detail":"unsupported media type: text/plain"}` + "\n"},
+		{"t5", "application/json", `{"name":"Ann","age":20,"email":"internal"}`, http.StatusInternalServerError,
+			`{"title":"Internal Server Error","status":500}` + "\n"},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest("POST", "/", test.contentType, test.body))
+		assert.Equal(t, test.status, w.Code, test.tag)
+		assert.Equal(t, test.response, w.Body.String(), test.tag)
+		if test.status != http.StatusOK {
+			assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), test.tag)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, httptest.NewRequest("GET", "/", nil), errors.New("unknown"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"title":"Internal Server Error","status":500}`+"\n", w.Body.String())
+}
+
+func TestHandlerMaxBodySize(t *testing.T) {
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 10
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request, u user) {})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("POST", "/", "application/json", `{"name":"Ann","age":20}`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, `{"title":"Request Entity Too Large","status":413,"detail":"invalid request payload: http: request body too large"}`+"\n", w.Body.String())
+}
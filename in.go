@@ -55,3 +55,11 @@ func (r InRule) ErrorObject(err Error) InRule {
 	r.err = err
 	return r
 }
+
+func (r InRule) checkStatic() string {
+	return checkTypes(r.elements)
+}
+
+func (r InRule) skipsEmpty() bool {
+	return true
+}
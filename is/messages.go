@@ -0,0 +1,132 @@
+package is
+
+import "github.com/khatibomar/kv"
+
+func init() {
+	for locale, messages := range map[string]map[string]string{
+		"ar": arabicMessages(),
+		"fr": frenchMessages(),
+	} {
+		if err := kv.DefaultCatalog.Add(locale, messages); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func arabicMessages() map[string]string {
+	return map[string]string{
+		ErrEmail.Code():            "يجب أن يكون عنوان بريد إلكتروني صالحًا",
+		ErrURL.Code():              "يجب أن يكون رابطًا صالحًا",
+		ErrRequestURL.Code():       "يجب أن يكون رابط طلب صالحًا",
+		ErrRequestURI.Code():       "يجب أن يكون معرّف طلب صالحًا",
+		ErrAlpha.Code():            "يجب أن يحتوي على أحرف إنجليزية فقط",
+		ErrDigit.Code():            "يجب أن يحتوي على أرقام فقط",
+		ErrAlphanumeric.Code():     "يجب أن يحتوي على أحرف إنجليزية وأرقام فقط",
+		ErrUTFLetter.Code():        "يجب أن يحتوي على أحرف يونيكود فقط",
+		ErrUTFDigit.Code():         "يجب أن يحتوي على أرقام عشرية يونيكود فقط",
+		ErrUTFLetterNumeric.Code(): "يجب أن يحتوي على أحرف وأرقام يونيكود فقط",
+		ErrUTFNumeric.Code():       "يجب أن يحتوي على رموز رقمية يونيكود فقط",
+		ErrLowerCase.Code():        "يجب أن يكون بأحرف صغيرة",
+		ErrUpperCase.Code():        "يجب أن يكون بأحرف كبيرة",
+		ErrHexadecimal.Code():      "يجب أن يكون رقمًا سداسيًا عشريًا صالحًا",
+		ErrHexColor.Code():         "يجب أن يكون رمز لون سداسيًا عشريًا صالحًا",
+		ErrRGBColor.Code():         "يجب أن يكون رمز لون RGB صالحًا",
+		ErrInt.Code():              "يجب أن يكون عددًا صحيحًا",
+		ErrFloat.Code():            "يجب أن يكون عددًا عشريًا",
+		ErrUUIDv3.Code():           "يجب أن يكون UUID v3 صالحًا",
+		ErrUUIDv4.Code():           "يجب أن يكون UUID v4 صالحًا",
+		ErrUUIDv5.Code():           "يجب أن يكون UUID v5 صالحًا",
+		ErrUUID.Code():             "يجب أن يكون UUID صالحًا",
+		ErrCreditCard.Code():       "يجب أن يكون رقم بطاقة ائتمان صالحًا",
+		ErrISBN10.Code():           "يجب أن يكون ISBN-10 صالحًا",
+		ErrISBN13.Code():           "يجب أن يكون ISBN-13 صالحًا",
+		ErrISBN.Code():             "يجب أن يكون ISBN صالحًا",
+		ErrJSON.Code():             "يجب أن يكون بتنسيق JSON صالح",
+		ErrASCII.Code():            "يجب أن يحتوي على أحرف ASCII فقط",
+		ErrPrintableASCII.Code():   "يجب أن يحتوي على أحرف ASCII قابلة للطباعة فقط",
+		ErrMultibyte.Code():        "يجب أن يحتوي على أحرف متعددة البايتات",
+		ErrFullWidth.Code():        "يجب أن يحتوي على أحرف كاملة العرض",
+		ErrHalfWidth.Code():        "يجب أن يحتوي على أحرف نصف العرض",
+		ErrVariableWidth.Code():    "يجب أن يحتوي على أحرف كاملة العرض ونصف العرض معًا",
+		ErrBase64.Code():           "يجب أن يكون مرمّزًا بصيغة Base64",
+		ErrDataURI.Code():          "يجب أن يكون رابط بيانات مرمّزًا بصيغة Base64",
+		ErrE164.Code():             "يجب أن يكون رقمًا صالحًا بصيغة E164",
+		ErrCountryCode2.Code():     "يجب أن يكون رمز دولة صالحًا من حرفين",
+		ErrCountryCode3.Code():     "يجب أن يكون رمز دولة صالحًا من ثلاثة أحرف",
+		ErrCurrencyCode.Code():     "يجب أن يكون رمز عملة صالحًا وفق ISO 4217",
+		ErrDialString.Code():       "يجب أن يكون سلسلة اتصال صالحة",
+		ErrMac.Code():              "يجب أن يكون عنوان MAC صالحًا",
+		ErrIP.Code():               "يجب أن يكون عنوان IP صالحًا",
+		ErrIPv4.Code():             "يجب أن يكون عنوان IPv4 صالحًا",
+		ErrIPv6.Code():             "يجب أن يكون عنوان IPv6 صالحًا",
+		ErrSubdomain.Code():        "يجب أن يكون نطاقًا فرعيًا صالحًا",
+		ErrDomain.Code():           "يجب أن يكون نطاقًا صالحًا",
+		ErrDNSName.Code():          "يجب أن يكون اسم DNS صالحًا",
+		ErrHost.Code():             "يجب أن يكون عنوان IP أو اسم DNS صالحًا",
+		ErrPort.Code():             "يجب أن يكون رقم منفذ صالحًا",
+		ErrMongoID.Code():          "يجب أن يكون معرّف MongoDB ObjectId صالحًا بترميز سداسي عشري",
+		ErrLatitude.Code():         "يجب أن يكون خط عرض صالحًا",
+		ErrLongitude.Code():        "يجب أن يكون خط طول صالحًا",
+		ErrSSN.Code():              "يجب أن يكون رقم ضمان اجتماعي صالحًا",
+		ErrSemver.Code():           "يجب أن يكون إصدارًا دلاليًا صالحًا",
+	}
+}
+
+func frenchMessages() map[string]string {
+	return map[string]string{
+		ErrEmail.Code():            "doit être une adresse e-mail valide",
+		ErrURL.Code():              "doit être une URL valide",
+		ErrRequestURL.Code():       "doit être une URL de requête valide",
+		ErrRequestURI.Code():       "doit être une URI de requête valide",
+		ErrAlpha.Code():            "ne doit contenir que des lettres anglaises",
+		ErrDigit.Code():            "ne doit contenir que des chiffres",
+		ErrAlphanumeric.Code():     "ne doit contenir que des lettres anglaises et des chiffres",
+		ErrUTFLetter.Code():        "ne doit contenir que des lettres Unicode",
+		ErrUTFDigit.Code():         "ne doit contenir que des chiffres décimaux Unicode",
+		ErrUTFLetterNumeric.Code(): "ne doit contenir que des lettres et des nombres Unicode",
+		ErrUTFNumeric.Code():       "ne doit contenir que des caractères numériques Unicode",
+		ErrLowerCase.Code():        "doit être en minuscules",
+		ErrUpperCase.Code():        "doit être en majuscules",
+		ErrHexadecimal.Code():      "doit être un nombre hexadécimal valide",
+		ErrHexColor.Code():         "doit être un code couleur hexadécimal valide",
+		ErrRGBColor.Code():         "doit être un code couleur RGB valide",
+		ErrInt.Code():              "doit être un nombre entier",
+		ErrFloat.Code():            "doit être un nombre à virgule flottante",
+		ErrUUIDv3.Code():           "doit être un UUID v3 valide",
+		ErrUUIDv4.Code():           "doit être un UUID v4 valide",
+		ErrUUIDv5.Code():           "doit être un UUID v5 valide",
+		ErrUUID.Code():             "doit être un UUID valide",
+		ErrCreditCard.Code():       "doit être un numéro de carte de crédit valide",
+		ErrISBN10.Code():           "doit être un ISBN-10 valide",
+		ErrISBN13.Code():           "doit être un ISBN-13 valide",
+		ErrISBN.Code():             "doit être un ISBN valide",
+		ErrJSON.Code():             "doit être au format JSON valide",
+		ErrASCII.Code():            "ne doit contenir que des caractères ASCII",
+		ErrPrintableASCII.Code():   "ne doit contenir que des caractères ASCII imprimables",
+		ErrMultibyte.Code():        "doit contenir des caractères multioctets",
+		ErrFullWidth.Code():        "doit contenir des caractères pleine chasse",
+		ErrHalfWidth.Code():        "doit contenir des caractères demi-chasse",
+		ErrVariableWidth.Code():    "doit contenir des caractères pleine chasse et demi-chasse",
+		ErrBase64.Code():           "doit être encodé en Base64",
+		ErrDataURI.Code():          "doit être une URI de données encodée en Base64",
+		ErrE164.Code():             "doit être un numéro E164 valide",
+		ErrCountryCode2.Code():     "doit être un code pays à deux lettres valide",
+		ErrCountryCode3.Code():     "doit être un code pays à trois lettres valide",
+		ErrCurrencyCode.Code():     "doit être un code de devise ISO 4217 valide",
+		ErrDialString.Code():       "doit être une chaîne de numérotation valide",
+		ErrMac.Code():              "doit être une adresse MAC valide",
+		ErrIP.Code():               "doit être une adresse IP valide",
+		ErrIPv4.Code():             "doit être une adresse IPv4 valide",
+		ErrIPv6.Code():             "doit être une adresse IPv6 valide",
+		ErrSubdomain.Code():        "doit être un sous-domaine valide",
+		ErrDomain.Code():           "doit être un domaine valide",
+		ErrDNSName.Code():          "doit être un nom DNS valide",
+		ErrHost.Code():             "doit être une adresse IP ou un nom DNS valide",
+		ErrPort.Code():             "doit être un numéro de port valide",
+		ErrMongoID.Code():          "doit être un ObjectId MongoDB valide encodé en hexadécimal",
+		ErrLatitude.Code():         "doit être une latitude valide",
+		ErrLongitude.Code():        "doit être une longitude valide",
+		ErrSSN.Code():              "doit être un numéro de sécurité sociale valide",
+		ErrSemver.Code():           "doit être une version sémantique valide",
+	}
+}
@@ -0,0 +1,26 @@
+package is
+
+import (
+	"testing"
+
+	"github.com/khatibomar/kv"
+)
+
+func TestMessages(t *testing.T) {
+	errs := []kv.Error{
+		ErrEmail, ErrURL, ErrRequestURL, ErrRequestURI, ErrAlpha, ErrDigit, ErrAlphanumeric,
+		ErrUTFLetter, ErrUTFDigit, ErrUTFLetterNumeric, ErrUTFNumeric, ErrLowerCase, ErrUpperCase,
+		ErrHexadecimal, ErrHexColor, ErrRGBColor, ErrInt, ErrFloat, ErrUUIDv3, ErrUUIDv4, ErrUUIDv5, ErrUUID,
+		ErrCreditCard, ErrISBN10, ErrISBN13, ErrISBN, ErrJSON, ErrASCII, ErrPrintableASCII, ErrMultibyte,
+		ErrFullWidth, ErrHalfWidth, ErrVariableWidth, ErrBase64, ErrDataURI, ErrE164, ErrCountryCode2,
+		ErrCountryCode3, ErrCurrencyCode, ErrDialString, ErrMac, ErrIP, ErrIPv4, ErrIPv6, ErrSubdomain,
+		ErrDomain, ErrDNSName, ErrHost, ErrPort, ErrMongoID, ErrLatitude, ErrLongitude, ErrSSN, ErrSemver,
+	}
+	for _, locale := range []string{"ar", "fr"} {
+		for _, err := range errs {
+			if _, ok := kv.DefaultCatalog.Translate(locale, err); !ok {
+				t.Errorf("%s: missing translation for %s", locale, err.Code())
+			}
+		}
+	}
+}
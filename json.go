@@ -0,0 +1,248 @@
+package kv
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// JSONFormat is the shape in which Errors are marshaled into JSON.
+type JSONFormat int
+
+const (
+	// JSONMessages marshals Errors into an object mapping each field to its error message, such as
+	// {"name":"cannot be blank","address":{"zip":"cannot be blank"}}.
+	JSONMessages JSONFormat = iota
+	// JSONObjects marshals Errors into an object mapping each field to an object holding the code, message
+	// and params of its error, such as {"name":{"code":"validation_required","message":"cannot be blank"}}.
+	// The object of a KeyError holds "key": true as well.
+	JSONObjects
+	// JSONFlat marshals Errors into an array of the entries returned by Errors.Flatten, such as
+	// [{"path":["name"],"code":"validation_required","message":"cannot be blank"}].
+	// An ErrorList that is not part of Errors is marshaled as with JSONObjects.
+	JSONFlat
+)
+
+// ErrorsJSONFormat is the format used by Errors.MarshalJSON and ErrorList.MarshalJSON.
+// It defaults to JSONMessages.
+var ErrorsJSONFormat = JSONMessages
+
+// jsonError is the JSON representation of an Error.
+type jsonError struct {
+	Code    string         `json:"code,omitempty"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+	Key     bool           `json:"key,omitempty"`
+}
+
+// MarshalJSONFormat converts the Errors into a valid JSON in the given format.
+func (es Errors) MarshalJSONFormat(format JSONFormat) ([]byte, error) {
+	if format == JSONFlat {
+		list := es.Flatten()
+		if list == nil {
+			list = []FlatError{}
+		}
+		return json.Marshal(list)
+	}
+	return json.Marshal(jsonValue(es, format))
+}
+
+// jsonValue returns the value to be marshaled into JSON for the given error.
+// Errors implementing json.Marshaler other than Error are marshaled by themselves.
+func jsonValue(err error, format JSONFormat) any {
+	switch e := err.(type) {
+	case Errors:
+		m := make(map[string]any, len(e))
+		for key, value := range e {
+			if value != nil {
+				m[key] = jsonValue(value, format)
+			}
+		}
+		return m
+	case ErrorList:
+		list := make([]any, 0, len(e))
+		for _, value := range e {
+			if value != nil {
+				list = append(list, jsonValue(value, format))
+			}
+		}
+		return list
+	case KeyError:
+		if format == JSONMessages {
+			return e.Error()
+		}
+		v := jsonValue(e.Err, format)
+		if je, ok := v.(jsonError); ok {
+			je.Key = true
+			return je
+		}
+		return v
+	case Error:
+		if format == JSONMessages {
+			return e.Error()
+		}
+		return jsonError{Code: e.Code(), Message: e.Error(), Params: e.Params()}
+	case json.Marshaler:
+		return e
+	}
+	if format == JSONMessages {
+		return err.Error()
+	}
+	return jsonError{Message: err.Error()}
+}
+
+// UnmarshalJSON restores Errors from a JSON in any of the formats produced by MarshalJSONFormat.
+// The errors are restored as ErrorObject, which lack the code and params when restored from JSONMessages.
+// Several errors of the same field are restored as an ErrorList. Numeric params are restored as float64.
+// Note that in the JSONMessages format, a nested field whose only subfield is named "message" cannot be
+// told apart from an error object, and is restored as an error.
+func (es *Errors) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		errs, err := errorsFromJSON(x)
+		if err != nil {
+			return err
+		}
+		*es = errs
+		return nil
+	case []any:
+		var list []FlatError
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		*es = errorsFromFlat(list)
+		return nil
+	}
+	return fmt.Errorf("cannot unmarshal %s into Errors", data)
+}
+
+// errorsFromJSON restores Errors from a JSON object in the JSONMessages or JSONObjects format.
+func errorsFromJSON(m map[string]any) (Errors, error) {
+	es := make(Errors, len(m))
+	for key, value := range m {
+		err, e := errorFromJSON(value)
+		if e != nil {
+			return nil, fmt.Errorf("%s: %w", key, e)
+		}
+		if err != nil {
+			es[key] = err
+		}
+	}
+	return es, nil
+}
+
+// errorFromJSON restores an error from a JSON value in the JSONMessages or JSONObjects format.
+func errorFromJSON(v any) (error, error) {
+	if m, ok := v.(map[string]any); ok && m["key"] == true {
+		m = maps.Clone(m)
+		delete(m, "key")
+		if eo, ok := errorObjectFromJSON(m); ok {
+			return KeyError{Err: eo}, nil
+		}
+	}
+	if eo, ok := errorObjectFromJSON(v); ok {
+		return eo, nil
+	}
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return errorsFromJSON(x)
+	case []any:
+		list := make(ErrorList, 0, len(x))
+		for _, value := range x {
+			err, e := errorFromJSON(value)
+			if e != nil {
+				return nil, e
+			}
+			if err != nil {
+				list = append(list, err)
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal %v into an error", v)
+}
+
+// errorObjectFromJSON restores an ErrorObject from a JSON string holding its message,
+// or from a JSON object holding its code, message and params.
+func errorObjectFromJSON(v any) (ErrorObject, bool) {
+	switch x := v.(type) {
+	case string:
+		return ErrorObject{message: x}, true
+	case map[string]any:
+		message, ok := x["message"].(string)
+		if !ok {
+			return ErrorObject{}, false
+		}
+		e := ErrorObject{message: message}
+		for key, value := range x {
+			switch key {
+			case "message":
+			case "code":
+				if e.code, ok = value.(string); !ok {
+					return ErrorObject{}, false
+				}
+			case "params":
+				if value == nil {
+					continue
+				}
+				if e.params, ok = value.(map[string]any); !ok {
+					return ErrorObject{}, false
+				}
+			default:
+				return ErrorObject{}, false
+			}
+		}
+		return renderedError(e.code, e.message, e.params), true
+	}
+	return ErrorObject{}, false
+}
+
+// errorsFromFlat restores Errors from the entries returned by Errors.Flatten.
+func errorsFromFlat(list []FlatError) Errors {
+	es := Errors{}
+	for _, fe := range list {
+		path := fe.Path
+		if len(path) == 0 {
+			path = Path{""}
+		}
+		parent := es
+		for _, key := range path[:len(path)-1] {
+			child, ok := parent[key].(Errors)
+			if !ok {
+				child = Errors{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		var err error = renderedError(fe.Code, fe.Message, fe.Params)
+		if fe.Key {
+			err = KeyError{Err: err}
+		}
+		key := path[len(path)-1]
+		switch e := parent[key].(type) {
+		case nil:
+			parent[key] = err
+		case ErrorList:
+			parent[key] = append(e, err)
+		default:
+			parent[key] = ErrorList{e, err}
+		}
+	}
+	return es
+}
+
+// renderedError creates an ErrorObject from a message that has already been rendered with the params.
+// Template actions in the message are escaped so that it is not rendered again.
+func renderedError(code, message string, params map[string]any) ErrorObject {
+	if len(params) > 0 {
+		message = strings.ReplaceAll(message, "{{", `{{"{{"}}`)
+	}
+	return ErrorObject{code: code, message: message, params: params}
+}
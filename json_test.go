@@ -0,0 +1,122 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestErrorObject_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(ErrLengthOutOfRange.SetParams(map[string]any{"min": 5, "max": 10}))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"code":"validation_length_out_of_range","message":"the length must be between 5 and 10","params":{"max":10,"min":5}}`, string(b))
+
+	b, err = json.Marshal(ErrRequired)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"code":"validation_required","message":"cannot be blank"}`, string(b))
+
+	var e ErrorObject
+	assert.NoError(t, json.Unmarshal(b, &e))
+	assert.Equal(t, "validation_required", e.Code())
+	assert.Equal(t, "cannot be blank", e.Error())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"code":"c","message":"must be {{x}} 5","params":{"min":5}}`), &e))
+	assert.Equal(t, "c", e.Code())
+	assert.Equal(t, "must be {{x}} 5", e.Error())
+	assert.Equal(t, 5.0, e.Params()["min"])
+
+	assert.NoError(t, json.Unmarshal([]byte(`"plain"`), &e))
+	assert.Equal(t, "", e.Code())
+	assert.Equal(t, "plain", e.Error())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"code":"c"}`), &e))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"message":"m","other":1}`), &e))
+	assert.NotNil(t, json.Unmarshal([]byte(`1`), &e))
+	assert.NotNil(t, json.Unmarshal([]byte(`{`), &e))
+}
+
+func TestErrorObject_MarshalText(t *testing.T) {
+	b, err := ErrLengthTooLong.SetParams(map[string]any{"max": 3}).(ErrorObject).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "the length must be no more than 3", string(b))
+
+	var e ErrorObject
+	assert.NoError(t, e.UnmarshalText([]byte("too long")))
+	assert.Equal(t, "too long", e.Error())
+}
+
+func TestErrors_MarshalJSONFormat(t *testing.T) {
+	errs := Errors{
+		"name":    ErrRequired,
+		"age":     ErrMinGreaterEqualThanRequired.SetParams(map[string]any{"threshold": 18}),
+		"address": Errors{"zip": errors.New("invalid zip"), "city": nil},
+		"tags":    ErrorList{ErrRequired, errors.New("too many")},
+	}
+	tests := []struct {
+		tag    string
+		format JSONFormat
+		json   string
+	}{
+		{"t1", JSONMessages, `{"address":{"zip":"invalid zip"},"age":"must be no less than 18","name":"cannot be blank","tags":["cannot be blank","too many"]}`},
+		{"t2", JSONObjects, `{"address":{"zip":{"message":"invalid zip"}},"age":{"code":"validation_min_greater_equal_than_required","message":"must be no less than 18","params":{"threshold":18}},` +
+			`"name":{"code":"validation_required","message":"cannot be blank"},"tags":[{"code":"validation_required","message":"cannot be blank"},{"message":"too many"}]}`},
+		{"t3", JSONFlat, `[{"path":["address","zip"],"message":"invalid zip"},{"path":["age"],"code":"validation_min_greater_equal_than_required","message":"must be no less than 18","params":{"threshold":18}},` +
+			`{"path":["name"],"code":"validation_required","message":"cannot be blank"},{"path":["tags"],"code":"validation_required","message":"cannot be blank"},{"path":["tags"],"message":"too many"}]`},
+	}
+	for _, test := range tests {
+		b, err := errs.MarshalJSONFormat(test.format)
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, test.json, string(b), test.tag)
+
+		// round trip
+		var es Errors
+		if assert.NoError(t, json.Unmarshal(b, &es), test.tag) {
+			b, err = es.MarshalJSONFormat(test.format)
+			assert.NoError(t, err, test.tag)
+			assert.Equal(t, test.json, string(b), test.tag)
+			if test.format != JSONMessages {
+				if e, ok := es["age"].(Error); assert.True(t, ok, test.tag) {
+					assert.Equal(t, ErrMinGreaterEqualThanRequired.Code(), e.Code(), test.tag)
+					assert.Equal(t, 18.0, e.Params()["threshold"], test.tag)
+				}
+			}
+			_, ok := es["tags"].(ErrorList)
+			assert.True(t, ok, test.tag)
+		}
+	}
+
+	b, err := Errors{}.MarshalJSONFormat(JSONFlat)
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+
+	// the global format
+	defer func(format JSONFormat) { ErrorsJSONFormat = format }(ErrorsJSONFormat)
+	ErrorsJSONFormat = JSONObjects
+	b, err = json.Marshal(Errors{"name": ErrRequired})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":{"code":"validation_required","message":"cannot be blank"}}`, string(b))
+	b, err = json.Marshal(ErrorList{ErrRequired})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"code":"validation_required","message":"cannot be blank"}]`, string(b))
+	ErrorsJSONFormat = JSONFlat
+	b, err = json.Marshal(Errors{"name": ErrRequired})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"path":["name"],"code":"validation_required","message":"cannot be blank"}]`, string(b))
+}
+
+func TestErrors_UnmarshalJSON(t *testing.T) {
+	var es Errors
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":"A1","b":null,"c":{"d":["D1",{"code":"x","message":"D2"}]}}`), &es))
+	assert.Equal(t, "a: A1; c: (d: D1, D2.).", es.Error())
+
+	assert.NoError(t, json.Unmarshal([]byte(`[{"path":[],"message":"root"},{"path":["a","b"],"message":"B1"},{"path":["a","b"],"message":"B2"},{"path":["a","b"],"message":"B3"}]`), &es))
+	assert.Equal(t, ": root; a: (b: B1, B2, B3.).", es.Error())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"abc"`), &es))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a":1}`), &es))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a":[1]}`), &es))
+	assert.NotNil(t, json.Unmarshal([]byte(`[1]`), &es))
+	assert.NotNil(t, json.Unmarshal([]byte(`{`), &es))
+}
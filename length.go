@@ -5,6 +5,7 @@
 package kv
 
 import (
+	"fmt"
 	"unicode/utf8"
 )
 
@@ -102,3 +103,17 @@ func buildLengthRuleError(min, max int) (err Error) {
 
 	return err.SetParams(map[string]any{"min": min, "max": max})
 }
+
+func (r LengthRule) checkStatic() string {
+	if r.min < 0 || r.max < 0 {
+		return fmt.Sprintf("the length range %d-%d is negative", r.min, r.max)
+	}
+	if r.max != 0 && r.min > r.max {
+		return fmt.Sprintf("the minimum length %d is greater than the maximum length %d, so no value is valid", r.min, r.max)
+	}
+	return ""
+}
+
+func (r LengthRule) skipsEmpty() bool {
+	return true
+}
@@ -30,9 +30,10 @@ type (
 
 	// KeyRules represents a rule set associated with a map key.
 	KeyRules struct {
-		key      any
-		optional bool
-		rules    []Rule[any]
+		key        any
+		optional   bool
+		collectAll bool
+		rules      []Rule[any]
 	}
 )
 
@@ -64,6 +65,8 @@ func (r MapRule) Validate(m any) error {
 }
 
 // ValidateWithContext checks if the given value is valid or not.
+// If the context carries a field mask, the keys absent from the mask are not validated.
+// Please refer to WithFieldMask for the details.
 func (r MapRule) ValidateWithContext(ctx context.Context, m any) error {
 	value := reflect.ValueOf(m)
 	if value.Kind() == reflect.Ptr {
@@ -89,24 +92,44 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m any) error {
 		}
 	}
 
+	mask, track := FieldMaskFromContext(ctx), tracksPath(ctx)
 	for _, kr := range r.keys {
-		var err error
-		if kv := reflect.ValueOf(kr.key); !kt.AssignableTo(kv.Type()) {
+		var (
+			err  error
+			name string
+		)
+		kctx, present := ctx, true
+		if mask != nil || track {
+			name = getErrorKeyName(kr.key)
+			if kctx, present = maskField(ctx, mask, name); present && track {
+				kctx = withPathElement(kctx, name)
+			}
+		}
+		if !present {
+			// skip the keys absent from the field mask
+		} else if kv := reflect.ValueOf(kr.key); !kt.AssignableTo(kv.Type()) {
 			err = ErrKeyWrongType
 		} else if vv := value.MapIndex(kv); !vv.IsValid() {
 			if !kr.optional {
 				err = ErrKeyMissing
 			}
-		} else if ctx == nil {
+		} else if kr.collectAll && kctx == nil {
+			err = ValidateAll(vv.Interface(), kr.rules...)
+		} else if kr.collectAll {
+			err = validateAllWithContext(kctx, vv.Interface(), kr.rules)
+		} else if kctx == nil {
 			err = Validate(vv.Interface(), kr.rules...)
 		} else {
-			err = ValidateWithContext(ctx, vv.Interface(), kr.rules...)
+			err = validateWithContext(kctx, vv.Interface(), kr.rules...)
 		}
 		if err != nil {
 			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
 				return err
 			}
-			errs[getErrorKeyName(kr.key)] = err
+			if name == "" {
+				name = getErrorKeyName(kr.key)
+			}
+			errs[name] = err
 		}
 		if !r.allowExtraKeys {
 			delete(extraKeys, kr.key)
@@ -139,7 +162,31 @@ func (r *KeyRules) Optional() *KeyRules {
 	return r
 }
 
+// CollectAll configures the key to be validated by all of its rules, instead of stopping at the first failing rule.
+// The errors of the failing rules are reported as an ErrorList. Please refer to ValidateAll for the details.
+func (r *KeyRules) CollectAll() *KeyRules {
+	r.collectAll = true
+	return r
+}
+
 // getErrorKeyName returns the name that should be used to represent the validation error of a map key.
 func getErrorKeyName(key any) string {
 	return fmt.Sprintf("%v", key)
 }
+
+func (r MapRule) ruleLists() []namedRules {
+	lists := make([]namedRules, len(r.keys))
+	for i, kr := range r.keys {
+		lists[i] = namedRules{name: getErrorKeyName(kr.key), rules: anyRules(kr.rules)}
+	}
+	return lists
+}
+
+func (r MapRule) referencesFields() bool {
+	for _, kr := range r.keys {
+		if rulesReferenceFields(kr.rules) {
+			return true
+		}
+	}
+	return false
+}
@@ -67,6 +67,11 @@ func TestMap(t *testing.T) {
 		{"t8.3", m4, []*KeyRules{Key("M3")}, ""},
 		// internal error
 		{"t9.1", m5, []*KeyRules{Key("A", &validateAbc{}), Key("B", Required), Key("A", &validateInternalError{})}, "error internal"},
+		{"t9.2", m5, []*KeyRules{Key("A", &validateInternalError{}, &validateXyz{}).CollectAll()}, "error internal"},
+		// collect all errors
+		{"t10.1", m1, []*KeyRules{Key("A", &validateXyz{}, Length(5, 10)).CollectAll(), Key("B", &validateAbc{}, Length(5, 10))}, "A: error xyz, the length must be between 5 and 10; B: error abc."},
+		{"t10.2", m1, []*KeyRules{Key("A", &validateXyz{}, Skip, Length(5, 10)).CollectAll()}, "A: error xyz."},
+		{"t10.3", m2, []*KeyRules{Key("E").CollectAll()}, "E: error 123."},
 	}
 	for _, test := range tests {
 		err1 := Validate(test.model, Map(test.rules...).AllowExtraKeys())
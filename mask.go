@@ -0,0 +1,174 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type (
+	// FieldMask is the set of fields that are present in the data being validated, such as the body of a PATCH request.
+	// When a struct or a map is validated with a context carrying a field mask, only the present fields are validated,
+	// so that rules like Required do not fail on the fields the client did not send.
+	//
+	// A field mask is a tree: each present field either is present as a whole, or has a sub-mask listing
+	// its present fields. A nil *FieldMask means that all fields are present.
+	FieldMask struct {
+		fields map[string]*FieldMask
+	}
+
+	fieldMaskKey struct{}
+)
+
+// NewFieldMask returns a field mask holding the given paths. A path is either a dotted path,
+// such as "address.zip", or a JSON Pointer (RFC 6901), such as "/address/zip". The path elements are
+// the names the fields have in the Errors, which are their JSON names by default. A path covers all
+// the fields nested in it, so NewFieldMask("address", "address.zip") is the same as NewFieldMask("address").
+func NewFieldMask(paths ...string) *FieldMask {
+	m := &FieldMask{fields: map[string]*FieldMask{}}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		var elems []string
+		if strings.HasPrefix(path, "/") {
+			elems = strings.Split(path[1:], "/")
+			for i, e := range elems {
+				elems[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(e)
+			}
+		} else {
+			elems = strings.Split(path, ".")
+		}
+		m.add(elems)
+	}
+	return m
+}
+
+// MaskFromKeys returns a field mask holding the paths whose value is true in the given map.
+// Please refer to NewFieldMask for the format of the paths.
+func MaskFromKeys(keys map[string]bool) *FieldMask {
+	var paths []string
+	for path, present := range keys {
+		if present {
+			paths = append(paths, path)
+		}
+	}
+	return NewFieldMask(paths...)
+}
+
+// MaskFromJSON returns a field mask holding the keys of the given JSON object. The keys of nested objects
+// become sub-masks, while arrays and other values are present as a whole. For example,
+//
+//	mask, err := kv.MaskFromJSON([]byte(`{"name": "x", "address": {"zip": "12345"}}`))
+//	// the same as kv.NewFieldMask("name", "address.zip")
+func MaskFromJSON(data []byte) (*FieldMask, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	m := &FieldMask{fields: make(map[string]*FieldMask, len(object))}
+	for key, value := range object {
+		if value = bytes.TrimSpace(value); len(value) == 0 || value[0] != '{' {
+			m.fields[key] = nil
+			continue
+		}
+		sub, err := MaskFromJSON(value)
+		if err != nil {
+			return nil, err
+		}
+		m.fields[key] = sub
+	}
+	return m, nil
+}
+
+// Has checks if the field with the given name is present.
+func (m *FieldMask) Has(name string) bool {
+	if m == nil {
+		return true
+	}
+	_, ok := m.fields[name]
+	return ok
+}
+
+// Sub returns the sub-mask of the field with the given name. It returns nil if the field is present as a whole.
+func (m *FieldMask) Sub(name string) *FieldMask {
+	if m == nil {
+		return nil
+	}
+	return m.fields[name]
+}
+
+// Paths returns the sorted dotted paths of the fields that are present as a whole.
+func (m *FieldMask) Paths() []string {
+	if m == nil {
+		return nil
+	}
+	var paths []string
+	for _, name := range slices.Sorted(maps.Keys(m.fields)) {
+		sub := m.fields[name]
+		if sub == nil {
+			paths = append(paths, name)
+			continue
+		}
+		for _, path := range sub.Paths() {
+			paths = append(paths, name+"."+path)
+		}
+	}
+	return paths
+}
+
+func (m *FieldMask) add(path []string) {
+	name := path[0]
+	sub, ok := m.fields[name]
+	if len(path) == 1 {
+		m.fields[name] = nil
+		return
+	}
+	if ok && sub == nil {
+		// the field is already present as a whole
+		return
+	}
+	if !ok {
+		sub = &FieldMask{fields: map[string]*FieldMask{}}
+		m.fields[name] = sub
+	}
+	sub.add(path[1:])
+}
+
+// WithFieldMask returns a copy of the context carrying the given field mask. When a struct is validated by
+// ValidateStructWithContext, or a map by MapRule or MapOfRule, with the returned context, the fields absent
+// from the mask are not validated, and the present fields are validated with their sub-masks.
+// Because the mask is carried by the context, the nested types implementing ValidatableWithContext pick up
+// their sub-masks as well. For example,
+//
+//	mask, err := kv.MaskFromJSON(body)
+//	...
+//	err = kv.ValidateWithContext(kv.WithFieldMask(ctx, mask), &patch)
+func WithFieldMask(ctx context.Context, mask *FieldMask) context.Context {
+	return context.WithValue(ctx, fieldMaskKey{}, mask)
+}
+
+// FieldMaskFromContext returns the field mask carried by the context, or nil if all fields are present.
+func FieldMaskFromContext(ctx context.Context) *FieldMask {
+	if ctx == nil {
+		return nil
+	}
+	mask, _ := ctx.Value(fieldMaskKey{}).(*FieldMask)
+	return mask
+}
+
+// maskField returns the context for validating the field with the given name, and whether the field should be
+// validated according to the field mask carried by the context, as returned by FieldMaskFromContext.
+// The mask is looked up once by the callers, so that the names of the fields are only computed when there is one.
+func maskField(ctx context.Context, mask *FieldMask, name string) (context.Context, bool) {
+	if mask == nil {
+		return ctx, true
+	}
+	if !mask.Has(name) {
+		return ctx, false
+	}
+	return WithFieldMask(ctx, mask.Sub(name)), true
+}
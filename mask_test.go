@@ -0,0 +1,149 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type maskAddress struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip"`
+}
+
+func (a maskAddress) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, &a,
+		Field(&a.Street, Required),
+		Field(&a.Zip, Required, Length(5, 5)),
+	)
+}
+
+type maskCustomer struct {
+	Name    string      `json:"name"`
+	Email   string      `json:"email"`
+	Address maskAddress `json:"address"`
+}
+
+func (c *maskCustomer) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, c,
+		Field(&c.Name, Required),
+		Field(&c.Email, Required),
+		Field(&c.Address),
+	)
+}
+
+func toAnySlice(s []string) []any {
+	r := make([]any, len(s))
+	for i, v := range s {
+		r[i] = v
+	}
+	return r
+}
+
+func TestNewFieldMask(t *testing.T) {
+	m := NewFieldMask("name", "address.zip", "/a~1b/c~0d", "")
+	assert.Equal(t, []any{"a/b.c~d", "address.zip", "name"}, toAnySlice(m.Paths()))
+	assert.True(t, m.Has("name"))
+	assert.True(t, m.Has("address"))
+	assert.False(t, m.Has("email"))
+	assert.Nil(t, m.Sub("name"))
+	assert.True(t, m.Sub("address").Has("zip"))
+	assert.False(t, m.Sub("address").Has("street"))
+
+	// a path covers the fields nested in it
+	m = NewFieldMask("address.zip", "address", "address.street")
+	assert.Equal(t, []any{"address"}, toAnySlice(m.Paths()))
+
+	// a nil mask holds all fields
+	var nilMask *FieldMask
+	assert.True(t, nilMask.Has("name"))
+	assert.Nil(t, nilMask.Sub("name"))
+	assert.Nil(t, nilMask.Paths())
+}
+
+func TestMaskFromKeys(t *testing.T) {
+	m := MaskFromKeys(map[string]bool{"name": true, "email": false, "address.zip": true})
+	assert.Equal(t, []any{"address.zip", "name"}, toAnySlice(m.Paths()))
+}
+
+func TestMaskFromJSON(t *testing.T) {
+	m, err := MaskFromJSON([]byte(`{"name": "x", "tags": [{"a": 1}], "address": {"zip": "1", "geo": {"lat": 1}}, "email": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"address.geo.lat", "address.zip", "email", "name", "tags"}, toAnySlice(m.Paths()))
+
+	_, err = MaskFromJSON([]byte(`[1, 2]`))
+	assert.NotNil(t, err)
+	_, err = MaskFromJSON([]byte(`{"name"`))
+	assert.NotNil(t, err)
+}
+
+func TestValidateStructWithFieldMask(t *testing.T) {
+	tests := []struct {
+		tag  string
+		mask *FieldMask
+		err  string
+	}{
+		{"t1", nil, "address: (street: cannot be blank; zip: the length must be exactly 5.); email: cannot be blank."},
+		{"t2", NewFieldMask("name"), ""},
+		{"t3", NewFieldMask("name", "email"), "email: cannot be blank."},
+		{"t4", NewFieldMask("address.zip"), "address: (zip: the length must be exactly 5.)."},
+		{"t5", NewFieldMask("address"), "address: (street: cannot be blank; zip: the length must be exactly 5.)."},
+		{"t6", NewFieldMask(), ""},
+	}
+	for _, test := range tests {
+		c := maskCustomer{Name: "John", Address: maskAddress{Zip: "123"}}
+		ctx := context.Background()
+		if test.mask != nil {
+			ctx = WithFieldMask(ctx, test.mask)
+		}
+		err := ValidateWithContext(ctx, &c)
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else {
+			assert.EqualError(t, err, test.err, test.tag)
+		}
+	}
+
+	// no context
+	c := maskCustomer{}
+	assert.EqualError(t, ValidateStruct(&c, Field(&c.Name, Required)), "name: cannot be blank.")
+	assert.Nil(t, FieldMaskFromContext(nil))
+}
+
+func TestStructRulesWithFieldMask(t *testing.T) {
+	rules := Struct[maskCustomer](
+		FieldOf("name", func(c *maskCustomer) *string { return &c.Name }, Typed[string](Required)),
+		FieldOf("email", func(c *maskCustomer) *string { return &c.Email }, Typed[string](Required)),
+	)
+	c := maskCustomer{}
+	ctx := WithFieldMask(context.Background(), NewFieldMask("email"))
+	assert.EqualError(t, rules.ValidateWithContext(ctx, &c), "email: cannot be blank.")
+	assert.EqualError(t, rules.Validate(&c), "email: cannot be blank; name: cannot be blank.")
+}
+
+func TestMapWithFieldMask(t *testing.T) {
+	ctx := WithFieldMask(context.Background(), NewFieldMask("name", "address.zip"))
+
+	m := map[string]any{"address": map[string]any{"street": "", "zip": "123"}}
+	err := Map(
+		Key("name", Required),
+		Key("email", Required),
+		Key("address", Map(
+			Key("street", Required),
+			Key("zip", Length(5, 5)),
+		)),
+	).ValidateWithContext(ctx, m)
+	assert.EqualError(t, err, "address: (zip: the length must be exactly 5.); name: required key is missing.")
+
+	rule := MapOf[string, string]().
+		Key("name", Typed[string](Required)).
+		Key("email", Typed[string](Required)).
+		Extra(Typed[string](Length(0, 3))).
+		MinKeys(3).
+		Dependent("name", "email")
+	err = rule.ValidateWithContext(ctx, map[string]string{"name": "", "nickname": "abcdef"})
+	assert.EqualError(t, err, "name: cannot be blank.")
+	err = rule.Validate(map[string]string{"name": "", "nickname": "abcdef"})
+	assert.EqualError(t, err, "must contain at least 3 keys")
+}
@@ -54,3 +54,14 @@ func (r MatchRule) ErrorObject(err Error) MatchRule {
 	r.err = err
 	return r
 }
+
+func (r MatchRule) checkStatic() string {
+	if r.re == nil {
+		return "the regular expression is nil"
+	}
+	return ""
+}
+
+func (r MatchRule) skipsEmpty() bool {
+	return true
+}
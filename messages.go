@@ -0,0 +1,133 @@
+package kv
+
+// newDefaultCatalog creates the catalog holding the translations of the errors returned by the built-in rules.
+func newDefaultCatalog() *Catalog {
+	c := NewCatalog()
+	for locale, messages := range map[string]map[string]string{
+		"ar": arabicMessages(),
+		"fr": frenchMessages(),
+	} {
+		if err := c.Add(locale, messages); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+func arabicMessages() map[string]string {
+	return map[string]string{
+		ErrNil.Code():                         "يجب أن يكون فارغًا",
+		ErrEmpty.Code():                       "يجب أن يكون فارغًا",
+		ErrDateInvalid.Code():                 "يجب أن يكون تاريخًا صالحًا",
+		ErrDateOutOfRange.Code():              "التاريخ خارج النطاق المسموح به",
+		ErrInInvalid.Code():                   "يجب أن تكون قيمة صالحة",
+		ErrLengthTooLong.Code():               "يجب ألا يزيد الطول عن {{.max}}",
+		ErrLengthTooShort.Code():              "يجب ألا يقل الطول عن {{.min}}",
+		ErrLengthInvalid.Code():               "يجب أن يكون الطول {{.min}} بالضبط",
+		ErrLengthOutOfRange.Code():            "يجب أن يكون الطول بين {{.min}} و{{.max}}",
+		ErrLengthEmptyRequired.Code():         "يجب أن تكون القيمة فارغة",
+		ErrKeyWrongType.Code():                "نوع المفتاح غير صحيح",
+		ErrKeyMissing.Code():                  "المفتاح المطلوب مفقود",
+		ErrKeyUnexpected.Code():               "مفتاح غير متوقع",
+		ErrMatchInvalid.Code():                "يجب أن يكون بتنسيق صالح",
+		ErrMinGreaterEqualThanRequired.Code(): "يجب ألا يقل عن {{.threshold}}",
+		ErrMaxLessEqualThanRequired.Code():    "يجب ألا يزيد عن {{.threshold}}",
+		ErrMinGreaterThanRequired.Code():      "يجب أن يكون أكبر من {{.threshold}}",
+		ErrMaxLessThanRequired.Code():         "يجب أن يكون أصغر من {{.threshold}}",
+		ErrMultipleOfInvalid.Code():           "يجب أن يكون من مضاعفات {{.base}}",
+		ErrNotInInvalid.Code():                "يجب ألا يكون ضمن القائمة",
+		ErrNotNilRequired.Code():              "مطلوب",
+		ErrRequired.Code():                    "لا يمكن أن يكون فارغًا",
+		ErrNilOrNotEmpty.Code():               "لا يمكن أن يكون فارغًا",
+		ErrTypeMismatch.Code():                "يجب أن تكون قيمة من النوع {{.type}}",
+		ErrEqualToField.Code():                "يجب أن يساوي {{.field}}",
+		ErrNotEqualToField.Code():             "يجب ألا يساوي {{.field}}",
+		ErrGreaterThanField.Code():            "يجب أن يكون أكبر من {{.field}}",
+		ErrGreaterEqualThanField.Code():       "يجب ألا يقل عن {{.field}}",
+		ErrLessThanField.Code():               "يجب أن يكون أصغر من {{.field}}",
+		ErrLessEqualThanField.Code():          "يجب ألا يزيد عن {{.field}}",
+		ErrRequiredWith.Code():                "لا يمكن أن يكون فارغًا عند وجود {{.fields}}",
+		ErrRequiredWithout.Code():             "لا يمكن أن يكون فارغًا عند غياب {{.fields}}",
+		ErrExcludedWith.Code():                "يجب أن يكون فارغًا عند وجود {{.fields}}",
+		ErrUnique.Code():                      "يجب ألا يحتوي على عناصر مكررة",
+		ErrMinItems.Code():                    "يجب أن يحتوي على {{.min}} عناصر على الأقل",
+		ErrMaxItems.Code():                    "يجب ألا يحتوي على أكثر من {{.max}} عناصر",
+		ErrContains.Code():                    "يجب أن يحتوي على {{.value}}",
+		ErrItemsIn.Code():                     "يجب أن يحتوي على عناصر صالحة فقط",
+		ErrSortedAscending.Code():             "يجب أن يكون مرتبًا ترتيبًا تصاعديًا",
+		ErrSortedDescending.Code():            "يجب أن يكون مرتبًا ترتيبًا تنازليًا",
+		ErrMapTooFewKeys.Code():               "يجب أن يحتوي على {{.min}} مفاتيح على الأقل",
+		ErrMapTooManyKeys.Code():              "يجب ألا يحتوي على أكثر من {{.max}} مفاتيح",
+		ErrKeyDependent.Code():                "المفتاح المطلوب مفقود عند وجود {{.key}}",
+		ErrDiscriminatorInvalid.Code():        "يجب أن يكون أحد {{.values}}",
+		ErrJSONObjectInvalid.Code():           "يجب أن يكون كائن JSON صالحًا",
+		ErrOneOfNone.Code():                   "يجب أن يطابق أحد المخططات المسموح بها",
+		ErrOneOfMultiple.Code():               "يجب أن يطابق مخططًا واحدًا فقط من المخططات المسموح بها",
+		ErrAnyOf.Code():                       "يجب أن يستوفي أحد الشروط التالية على الأقل: {{.errors}}",
+		ErrNot.Code():                         "غير مسموح به",
+		ErrImmutable.Code():                   "لا يمكن تغييره",
+		ErrOneWay.Code():                      "لا يمكن تغييره من {{.value}}",
+		ErrTransition.Code():                  "لا يمكن التغيير من {{.from}} إلى {{.to}}",
+		ErrIncreasing.Code():                  "لا يمكن إنقاصه",
+		ErrDecreasing.Code():                  "لا يمكن زيادته",
+	}
+}
+
+func frenchMessages() map[string]string {
+	return map[string]string{
+		ErrNil.Code():                         "doit être vide",
+		ErrEmpty.Code():                       "doit être vide",
+		ErrDateInvalid.Code():                 "doit être une date valide",
+		ErrDateOutOfRange.Code():              "la date est hors de la plage autorisée",
+		ErrInInvalid.Code():                   "doit être une valeur valide",
+		ErrLengthTooLong.Code():               "la longueur ne doit pas dépasser {{.max}}",
+		ErrLengthTooShort.Code():              "la longueur doit être d'au moins {{.min}}",
+		ErrLengthInvalid.Code():               "la longueur doit être exactement {{.min}}",
+		ErrLengthOutOfRange.Code():            "la longueur doit être comprise entre {{.min}} et {{.max}}",
+		ErrLengthEmptyRequired.Code():         "la valeur doit être vide",
+		ErrKeyWrongType.Code():                "la clé n'est pas du bon type",
+		ErrKeyMissing.Code():                  "une clé obligatoire est manquante",
+		ErrKeyUnexpected.Code():               "clé inattendue",
+		ErrMatchInvalid.Code():                "doit être dans un format valide",
+		ErrMinGreaterEqualThanRequired.Code(): "doit être supérieur ou égal à {{.threshold}}",
+		ErrMaxLessEqualThanRequired.Code():    "doit être inférieur ou égal à {{.threshold}}",
+		ErrMinGreaterThanRequired.Code():      "doit être supérieur à {{.threshold}}",
+		ErrMaxLessThanRequired.Code():         "doit être inférieur à {{.threshold}}",
+		ErrMultipleOfInvalid.Code():           "doit être un multiple de {{.base}}",
+		ErrNotInInvalid.Code():                "ne doit pas figurer dans la liste",
+		ErrNotNilRequired.Code():              "est obligatoire",
+		ErrRequired.Code():                    "ne peut pas être vide",
+		ErrNilOrNotEmpty.Code():               "ne peut pas être vide",
+		ErrTypeMismatch.Code():                "doit être une valeur de type {{.type}}",
+		ErrEqualToField.Code():                "doit être égal à {{.field}}",
+		ErrNotEqualToField.Code():             "ne doit pas être égal à {{.field}}",
+		ErrGreaterThanField.Code():            "doit être supérieur à {{.field}}",
+		ErrGreaterEqualThanField.Code():       "doit être supérieur ou égal à {{.field}}",
+		ErrLessThanField.Code():               "doit être inférieur à {{.field}}",
+		ErrLessEqualThanField.Code():          "doit être inférieur ou égal à {{.field}}",
+		ErrRequiredWith.Code():                "ne peut pas être vide lorsque {{.fields}} est renseigné",
+		ErrRequiredWithout.Code():             "ne peut pas être vide lorsque {{.fields}} est absent",
+		ErrExcludedWith.Code():                "doit être vide lorsque {{.fields}} est renseigné",
+		ErrUnique.Code():                      "ne doit pas contenir d'éléments en double",
+		ErrMinItems.Code():                    "doit contenir au moins {{.min}} éléments",
+		ErrMaxItems.Code():                    "ne doit pas contenir plus de {{.max}} éléments",
+		ErrContains.Code():                    "doit contenir {{.value}}",
+		ErrItemsIn.Code():                     "doit contenir uniquement des éléments valides",
+		ErrSortedAscending.Code():             "doit être trié par ordre croissant",
+		ErrSortedDescending.Code():            "doit être trié par ordre décroissant",
+		ErrMapTooFewKeys.Code():               "doit contenir au moins {{.min}} clés",
+		ErrMapTooManyKeys.Code():              "ne doit pas contenir plus de {{.max}} clés",
+		ErrKeyDependent.Code():                "clé requise manquante lorsque {{.key}} est présente",
+		ErrDiscriminatorInvalid.Code():        "doit être l'une des valeurs {{.values}}",
+		ErrJSONObjectInvalid.Code():           "doit être un objet JSON valide",
+		ErrOneOfNone.Code():                   "doit correspondre à l'un des schémas autorisés",
+		ErrOneOfMultiple.Code():               "doit correspondre à exactement un des schémas autorisés",
+		ErrAnyOf.Code():                       "doit satisfaire au moins une des conditions : {{.errors}}",
+		ErrNot.Code():                         "n'est pas autorisé",
+		ErrImmutable.Code():                   "ne peut pas être modifié",
+		ErrOneWay.Code():                      "ne peut pas être modifié à partir de {{.value}}",
+		ErrTransition.Code():                  "ne peut pas passer de {{.from}} à {{.to}}",
+		ErrIncreasing.Code():                  "ne peut pas être diminué",
+		ErrDecreasing.Code():                  "ne peut pas être augmenté",
+	}
+}
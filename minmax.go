@@ -193,3 +193,19 @@ func (r TimeThresholdRule) ErrorObject(err Error) TimeThresholdRule {
 	r.err = err
 	return r
 }
+
+func (r ThresholdRule[T]) bound() (any, bool, bool) {
+	return r.threshold, r.operator == greaterThan || r.operator == greaterEqualThan, r.operator == greaterThan || r.operator == lessThan
+}
+
+func (r TimeThresholdRule) bound() (any, bool, bool) {
+	return r.threshold, r.operator == greaterThan || r.operator == greaterEqualThan, r.operator == greaterThan || r.operator == lessThan
+}
+
+func (r ThresholdRule[T]) skipsEmpty() bool {
+	return true
+}
+
+func (r TimeThresholdRule) skipsEmpty() bool {
+	return true
+}
@@ -49,3 +49,11 @@ func (r NotInRule) ErrorObject(err Error) NotInRule {
 	r.err = err
 	return r
 }
+
+func (r NotInRule) checkStatic() string {
+	return checkTypes(r.elements)
+}
+
+func (r NotInRule) skipsEmpty() bool {
+	return true
+}
@@ -0,0 +1,177 @@
+package kv
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Path is the location of a validation error in the data being validated.
+	// Its elements are the struct field names, map keys and slice indices leading to the invalid value.
+	Path []string
+
+	// FlatError is a validation error located by its path, as returned by Errors.Flatten.
+	// The error of a map key, reported as a KeyError, has the path of its entry and Key set to true.
+	FlatError struct {
+		Path    Path           `json:"path"`
+		Code    string         `json:"code,omitempty"`
+		Message string         `json:"message"`
+		Params  map[string]any `json:"params,omitempty"`
+		Key     bool           `json:"key,omitempty"`
+	}
+)
+
+type (
+	pathKey    struct{}
+	keyPathKey struct{}
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Pointer returns the path as a JSON Pointer (RFC 6901), such as "/address/zip".
+// As JSON Pointers cannot locate the name of an object member, the error of a map key is located by the pointer
+// of its entry, and told apart by FlatError.Key.
+func (p Path) Pointer() string {
+	var s strings.Builder
+	for _, e := range p {
+		s.WriteByte('/')
+		s.WriteString(pointerEscaper.Replace(e))
+	}
+	return s.String()
+}
+
+// String returns the path in dotted/bracket notation, such as "items[3].sku".
+// Indices are enclosed in brackets, and keys that are not identifiers are quoted, as in `labels["app.kubernetes.io"]`.
+func (p Path) String() string {
+	var s strings.Builder
+	for i, e := range p {
+		switch {
+		case isIndex(e):
+			s.WriteString("[" + e + "]")
+		case isIdentifier(e):
+			if i > 0 {
+				s.WriteByte('.')
+			}
+			s.WriteString(e)
+		default:
+			s.WriteString("[" + strconv.Quote(e) + "]")
+		}
+	}
+	return s.String()
+}
+
+// Flatten returns the validation errors nested in Errors as a list sorted by path.
+// Each error of an ErrorList becomes a separate entry with the same path.
+// Nil errors are ignored.
+func (es Errors) Flatten() []FlatError {
+	var list []FlatError
+	es.flatten(nil, &list)
+	slices.SortStableFunc(list, func(a, b FlatError) int {
+		return comparePaths(a.Path, b.Path)
+	})
+	return list
+}
+
+func (es Errors) flatten(path Path, list *[]FlatError) {
+	for key, err := range es {
+		flattenError(append(path[:len(path):len(path)], key), err, list)
+	}
+}
+
+func flattenError(path Path, err error, list *[]FlatError) {
+	switch e := err.(type) {
+	case nil:
+	case Errors:
+		e.flatten(path, list)
+	case ErrorList:
+		for _, err := range e {
+			flattenError(path, err, list)
+		}
+	case KeyError:
+		n := len(*list)
+		flattenError(path, e.Err, list)
+		for i := n; i < len(*list); i++ {
+			(*list)[i].Key = true
+		}
+	case Error:
+		*list = append(*list, FlatError{Path: path, Code: e.Code(), Message: e.Error(), Params: e.Params()})
+	default:
+		*list = append(*list, FlatError{Path: path, Message: e.Error()})
+	}
+}
+
+// comparePaths compares two paths element by element. Indices are compared numerically.
+func comparePaths(a, b Path) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		if isIndex(x) && isIndex(y) && len(x) != len(y) {
+			return len(x) - len(y)
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// isIndex checks if a path element is a slice index.
+func isIndex(s string) bool {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifier checks if a path element can be written after a dot.
+func isIdentifier(s string) bool {
+	for i, c := range s {
+		if c != '_' && c != '$' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// withPathElement returns a copy of the context whose path has the given element appended.
+// The path locates the value being validated, and is only tracked for the features that report it.
+func withPathElement(ctx context.Context, e string) context.Context {
+	path := pathFromContext(ctx)
+	return context.WithValue(ctx, pathKey{}, append(path[:len(path):len(path)], e))
+}
+
+// withKeyPathElement returns a copy of the context whose path has the name of a map entry appended,
+// for validating the key of the entry rather than its value.
+func withKeyPathElement(ctx context.Context, e string) context.Context {
+	ctx = withPathElement(ctx, e)
+	return context.WithValue(ctx, keyPathKey{}, len(pathFromContext(ctx)))
+}
+
+// isKeyPath checks if the value being validated is a map key, as set by withKeyPathElement.
+func isKeyPath(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	n, ok := ctx.Value(keyPathKey{}).(int)
+	return ok && n == len(pathFromContext(ctx))
+}
+
+// pathFromContext returns the path of the value being validated.
+func pathFromContext(ctx context.Context) Path {
+	if ctx == nil {
+		return nil
+	}
+	path, _ := ctx.Value(pathKey{}).(Path)
+	return path
+}
+
+// tracksPath checks if the path of the value being validated should be tracked in the context.
+func tracksPath(ctx context.Context) bool {
+	return defaultsPlanFromContext(ctx) != nil || warningsFromContext(ctx) != nil || traceFromContext(ctx) != nil
+}
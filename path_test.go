@@ -0,0 +1,87 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		tag     string
+		path    Path
+		pointer string
+		str     string
+	}{
+		{"t1", nil, "", ""},
+		{"t2", Path{"address", "zip"}, "/address/zip", "address.zip"},
+		{"t3", Path{"items", "3", "sku"}, "/items/3/sku", "items[3].sku"},
+		{"t4", Path{"0", "name"}, "/0/name", "[0].name"},
+		{"t5", Path{"labels", "app.io/name"}, "/labels/app.io~1name", `labels["app.io/name"]`},
+		{"t6", Path{"a~b", "_c1"}, "/a~0b/_c1", `["a~b"]._c1`},
+		{"t7", Path{"x", "007", ""}, "/x/007/", `x["007"][""]`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.pointer, test.path.Pointer(), test.tag)
+		assert.Equal(t, test.str, test.path.String(), test.tag)
+	}
+}
+
+func TestErrors_Flatten(t *testing.T) {
+	assert.Equal(t, 0, len(Errors{}.Flatten()))
+
+	a := struct {
+		Name    string
+		Address struct {
+			Zip string `json:"zip"`
+		} `json:"address"`
+		Items []string `json:"items"`
+		Tags  map[string]string
+	}{Items: slices.Repeat([]string{"xyzxyz"}, 12), Tags: map[string]string{"a b": "x"}}
+	a.Items[2] = "abc"
+	a.Items[10] = "abc"
+	err := ValidateStruct(&a,
+		Field(&a.Name, Required, Length(5, 10)),
+		Field(&a.Address, By(func(any) error {
+			return ValidateStruct(&a.Address, Field(&a.Address.Zip, Required))
+		})),
+		Field(&a.Items, Each(By(func(value any) error {
+			return ValidateAll(value, Length(5, 10), &validateXyz{})
+		}))),
+		Field(&a.Tags, Map(Key("a b", In("y"))).AllowExtraKeys()),
+	)
+
+	es, ok := err.(Errors)
+	if !assert.True(t, ok) {
+		return
+	}
+	list := es.Flatten()
+	paths := make([]string, len(list))
+	for i, e := range list {
+		paths[i] = e.Path.String()
+	}
+	assert.Equal(t, `Name Tags["a b"] address.zip items[2] items[2] items[10] items[10]`, strings.Join(paths, " "))
+	assert.Equal(t, ErrRequired.Code(), list[0].Code)
+	assert.Equal(t, "cannot be blank", list[0].Message)
+	assert.Nil(t, list[0].Params)
+	assert.Equal(t, "/address/zip", list[2].Path.Pointer())
+	assert.Equal(t, ErrLengthOutOfRange.Code(), list[3].Code)
+	assert.Equal(t, "the length must be between 5 and 10", list[3].Message)
+	assert.Equal(t, map[string]any{"min": 5, "max": 10}, list[3].Params)
+	assert.Equal(t, "", list[4].Code)
+	assert.Equal(t, "error xyz", list[4].Message)
+
+	b, err := json.Marshal(list[:1])
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"path":["Name"],"code":"validation_required","message":"cannot be blank"}]`, string(b))
+
+	list = Errors{"a": nil, "b": errors.New("b1")}.Flatten()
+	if assert.Equal(t, 1, len(list)) {
+		assert.Equal(t, "/b", list[0].Path.Pointer())
+		assert.Equal(t, "b1", list[0].Message)
+	}
+}
@@ -0,0 +1,96 @@
+// Package problem renders validation errors as RFC 7807 problem details.
+//
+// A validation error, such as the kv.Errors returned by kv.ValidateStruct, is converted into an
+// "application/problem+json" document with the status 422 and an "errors" member listing every violation
+// with its location, code, message and params. An internal error is converted into a document with the
+// status 500 that does not disclose the error message.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/khatibomar/kv"
+)
+
+// ContentType is the media type of the problem details documents.
+const ContentType = "application/problem+json"
+
+type (
+	// Details is an RFC 7807 problem details document.
+	// Type and Instance are left empty; set them to identify the problem type and occurrence of an API.
+	Details struct {
+		Type     string      `json:"type,omitempty"`
+		Title    string      `json:"title"`
+		Status   int         `json:"status"`
+		Detail   string      `json:"detail,omitempty"`
+		Instance string      `json:"instance,omitempty"`
+		Errors   []Violation `json:"errors,omitempty"`
+	}
+
+	// Violation describes a single validation error of a problem.
+	Violation struct {
+		// Pointer is the JSON Pointer of the invalid value, or an empty string for the whole document.
+		Pointer string `json:"pointer"`
+		// Field is the location of the invalid value in dotted/bracket notation.
+		Field   string         `json:"field,omitempty"`
+		Code    string         `json:"code,omitempty"`
+		Message string         `json:"message"`
+		Params  map[string]any `json:"params,omitempty"`
+		// Key tells if the violation is about the name of the object member located by Pointer,
+		// such as an invalid map key, rather than its value.
+		Key bool `json:"key,omitempty"`
+	}
+)
+
+// New converts the given error into problem details. It returns nil if the error is nil.
+//
+// An error implementing kv.InternalError results in a problem with the status 500 (Internal Server Error).
+// Any other error is treated as a validation error and results in a problem with the status 422
+// (Unprocessable Entity), whose violations are built from kv.Errors, kv.ErrorList and kv.Error.
+func New(err error) *Details {
+	if err == nil {
+		return nil
+	}
+	if ie, ok := err.(kv.InternalError); ok && ie.InternalError() != nil {
+		return &Details{
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	d := &Details{
+		Title:  "Validation Failed",
+		Status: http.StatusUnprocessableEntity,
+		Detail: "The request contains invalid values.",
+	}
+	for _, e := range (kv.Errors{"": err}).Flatten() {
+		// the first element of the path is the placeholder key of the root error
+		path := e.Path[1:]
+		d.Errors = append(d.Errors, Violation{
+			Pointer: path.Pointer(),
+			Field:   path.String(),
+			Code:    e.Code,
+			Message: e.Message,
+			Params:  e.Params,
+			Key:     e.Key,
+		})
+	}
+	return d
+}
+
+// ServeHTTP writes the problem details as the response.
+func (d *Details) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(d.Status)
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+// Write converts the given error into problem details and writes them as the response.
+// Nothing is written if the error is nil.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	if d := New(err); d != nil {
+		d.ServeHTTP(w, r)
+	}
+}
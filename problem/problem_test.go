@@ -0,0 +1,96 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khatibomar/kv"
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(nil))
+
+	d := New(kv.NewInternalError(errors.New("db is down")))
+	assert.Equal(t, http.StatusInternalServerError, d.Status)
+	assert.Equal(t, "Internal Server Error", d.Title)
+	assert.Equal(t, "", d.Detail)
+	assert.Equal(t, 0, len(d.Errors))
+
+	d = New(kv.Validate("abc", kv.Length(5, 10)))
+	assert.Equal(t, http.StatusUnprocessableEntity, d.Status)
+	if assert.Equal(t, 1, len(d.Errors)) {
+		v := d.Errors[0]
+		assert.Equal(t, "", v.Pointer)
+		assert.Equal(t, "", v.Field)
+		assert.Equal(t, kv.ErrLengthOutOfRange.Code(), v.Code)
+		assert.Equal(t, "the length must be between 5 and 10", v.Message)
+		assert.Equal(t, map[string]any{"min": 5, "max": 10}, v.Params)
+	}
+
+	d = New(kv.ErrorList{kv.ErrRequired, errors.New("plain")})
+	if assert.Equal(t, 2, len(d.Errors)) {
+		assert.Equal(t, kv.ErrRequired.Code(), d.Errors[0].Code)
+		assert.Equal(t, "", d.Errors[1].Code)
+		assert.Equal(t, "plain", d.Errors[1].Message)
+	}
+
+	d = New(kv.Errors{
+		"address": kv.Errors{"zip": kv.ErrRequired},
+		"items":   kv.Errors{"3": kv.Errors{"sku": kv.ErrRequired}},
+	})
+	if assert.Equal(t, 2, len(d.Errors)) {
+		assert.Equal(t, "/address/zip", d.Errors[0].Pointer)
+		assert.Equal(t, "address.zip", d.Errors[0].Field)
+		assert.Equal(t, "/items/3/sku", d.Errors[1].Pointer)
+		assert.Equal(t, "items[3].sku", d.Errors[1].Field)
+	}
+
+	// the errors of map keys are located by their entries
+	labels := map[string]string{"Team!": ""}
+	d = New(kv.ValidateValue(labels, kv.EachEntry(
+		[]kv.Rule[string]{kv.Typed[string](kv.Length(1, 3))},
+		[]kv.Rule[string]{kv.Typed[string](kv.Required)},
+	)))
+	if assert.Equal(t, 2, len(d.Errors)) {
+		assert.Equal(t, "/Team!", d.Errors[0].Pointer)
+		assert.Equal(t, kv.ErrLengthOutOfRange.Code(), d.Errors[0].Code)
+		assert.True(t, d.Errors[0].Key)
+		assert.Equal(t, "/Team!", d.Errors[1].Pointer)
+		assert.Equal(t, kv.ErrRequired.Code(), d.Errors[1].Code)
+		assert.False(t, d.Errors[1].Key)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	a := struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}{Age: 10}
+	err := kv.ValidateStruct(&a,
+		kv.Field(&a.Name, kv.Required),
+		kv.Field(&a.Age, kv.Any(kv.Min(18))),
+	)
+
+	w := httptest.NewRecorder()
+	Write(w, httptest.NewRequest(http.MethodPost, "/users", nil), err)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"title":"Validation Failed","status":422,"detail":"The request contains invalid values.","errors":[`+
+		`{"pointer":"/age","field":"age","code":"validation_min_greater_equal_than_required","message":"must be no less than 18","params":{"threshold":18}},`+
+		`{"pointer":"/name","field":"name","code":"validation_required","message":"cannot be blank"}]}`+"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	d := New(kv.NewInternalError(errors.New("db is down")))
+	d.Type = "https://example.com/problems/internal"
+	d.Instance = "/users"
+	d.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"type":"https://example.com/problems/internal","title":"Internal Server Error","status":500,"instance":"/users"}`+"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	Write(w, httptest.NewRequest(http.MethodPost, "/users", nil), nil)
+	assert.Equal(t, 0, w.Body.Len())
+}
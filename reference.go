@@ -0,0 +1,349 @@
+package kv
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrFieldReference is the error that a field referenced by a rule cannot be found in the struct being validated.
+	ErrFieldReference = errors.New("the referenced field cannot be found in the struct being validated")
+
+	// ErrEqualToField is the error that returns when a value is not equal to the value of another field.
+	ErrEqualToField = NewError("validation_equal_to_field", "must be equal to {{.field}}")
+	// ErrNotEqualToField is the error that returns when a value is equal to the value of another field.
+	ErrNotEqualToField = NewError("validation_not_equal_to_field", "must not be equal to {{.field}}")
+	// ErrGreaterThanField is the error that returns when a value is not greater than the value of another field.
+	ErrGreaterThanField = NewError("validation_greater_than_field", "must be greater than {{.field}}")
+	// ErrGreaterEqualThanField is the error that returns when a value is less than the value of another field.
+	ErrGreaterEqualThanField = NewError("validation_greater_equal_than_field", "must be no less than {{.field}}")
+	// ErrLessThanField is the error that returns when a value is not less than the value of another field.
+	ErrLessThanField = NewError("validation_less_than_field", "must be less than {{.field}}")
+	// ErrLessEqualThanField is the error that returns when a value is greater than the value of another field.
+	ErrLessEqualThanField = NewError("validation_less_equal_than_field", "must be no greater than {{.field}}")
+	// ErrRequiredWith is the error that returns when a value is empty while any of the other fields is not.
+	ErrRequiredWith = NewError("validation_required_with", "cannot be blank when {{.fields}} is present")
+	// ErrRequiredWithout is the error that returns when a value is empty while any of the other fields is empty too.
+	ErrRequiredWithout = NewError("validation_required_without", "cannot be blank when {{.fields}} is missing")
+	// ErrExcludedWith is the error that returns when a value is not empty while any of the other fields is not empty too.
+	ErrExcludedWith = NewError("validation_excluded_with", "must be blank when {{.fields}} is present")
+)
+
+const (
+	equalTo = lessEqualThan + 1 + iota
+	notEqualTo
+)
+
+const (
+	requiredWith = iota
+	requiredWithout
+	excludedWith
+)
+
+type (
+	// FieldCompareRule is a validation rule that compares a value with the value of another field of the same struct.
+	FieldCompareRule struct {
+		other    any
+		operator int
+		compare  func(value, other any) (int, error)
+		err      Error
+	}
+
+	// FieldPresenceRule is a validation rule that checks if a value is present depending on the presence
+	// of other fields of the same struct.
+	FieldPresenceRule struct {
+		others []any
+		mode   int
+		err    Error
+	}
+
+	// fieldReferrer is implemented by rules referencing other fields of the struct being validated,
+	// and by rules holding other rules, so that the struct is only made available to the rules needing it.
+	fieldReferrer interface {
+		referencesFields() bool
+	}
+
+	structKey struct{}
+)
+
+// EqualTo returns a validation rule that checks if a value is equal to the value of another field.
+// The other field must be specified as a pointer to a field of the struct being validated by ValidateStruct,
+// and its error name is given as the "field" param of the error. For example,
+//
+//	kv.Field(&f.PasswordConfirm, kv.EqualTo(&f.Password))
+//
+// Time values are compared with time.Time.Equal. An empty value is considered valid.
+func EqualTo(other any) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: equalTo, compare: compareEqual, err: ErrEqualToField}
+}
+
+// NotEqualTo returns a validation rule that checks if a value is not equal to the value of another field.
+// Please refer to EqualTo for how to specify the other field. An empty value is considered valid.
+func NotEqualTo(other any) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: notEqualTo, compare: compareEqual, err: ErrNotEqualToField}
+}
+
+// GreaterThan returns a validation rule that checks if a value is greater than the value of another field.
+// Please refer to EqualTo for how to specify the other field. By calling OrEqual, the rule will check if
+// the value is greater or equal than the value of the other field. An empty value is considered valid,
+// and the value is not compared if the other field is empty.
+func GreaterThan[T Ordered](other *T) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: greaterThan, compare: compareOrdered[T], err: ErrGreaterThanField}
+}
+
+// LessThan returns a validation rule that checks if a value is less than the value of another field.
+// Please refer to GreaterThan for the details.
+func LessThan[T Ordered](other *T) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: lessThan, compare: compareOrdered[T], err: ErrLessThanField}
+}
+
+// GreaterThanTime returns a validation rule that checks if a time is after the time of another field.
+// Please refer to GreaterThan for the details.
+func GreaterThanTime(other *time.Time) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: greaterThan, compare: compareTime, err: ErrGreaterThanField}
+}
+
+// LessThanTime returns a validation rule that checks if a time is before the time of another field.
+// Please refer to GreaterThan for the details.
+func LessThanTime(other *time.Time) FieldCompareRule {
+	return FieldCompareRule{other: other, operator: lessThan, compare: compareTime, err: ErrLessThanField}
+}
+
+// OrEqual sets the comparison to include the value of the other field.
+// It has no effect on EqualTo and NotEqualTo.
+func (r FieldCompareRule) OrEqual() FieldCompareRule {
+	if r.operator == greaterThan {
+		r.operator = greaterEqualThan
+		r.err = ErrGreaterEqualThanField
+	} else if r.operator == lessThan {
+		r.operator = lessEqualThan
+		r.err = ErrLessEqualThanField
+	}
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+// The other field is looked up in the struct that ValidateStruct makes available through the context,
+// so an InternalError wrapping ErrFieldReference is returned when the rule is validated on its own.
+func (r FieldCompareRule) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r FieldCompareRule) ValidateWithContext(ctx context.Context, value any) error {
+	value, isNil := Indirect(value)
+	if isNil || IsEmpty(value) {
+		return nil
+	}
+	name, other, err := referencedField(ctx, r.other)
+	if err != nil {
+		return err
+	}
+	other, isNil = Indirect(other)
+	if (isNil || IsEmpty(other)) && r.operator != equalTo && r.operator != notEqualTo {
+		return nil
+	}
+
+	c, err := r.compare(value, other)
+	if err != nil {
+		return err
+	}
+	var valid bool
+	switch r.operator {
+	case equalTo:
+		valid = c == 0
+	case notEqualTo:
+		valid = c != 0
+	case greaterThan:
+		valid = c > 0
+	case greaterEqualThan:
+		valid = c >= 0
+	case lessThan:
+		valid = c < 0
+	case lessEqualThan:
+		valid = c <= 0
+	}
+	if valid {
+		return nil
+	}
+	return r.err.SetParams(map[string]any{"field": name})
+}
+
+// Error sets the error message for the rule.
+func (r FieldCompareRule) Error(message string) FieldCompareRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r FieldCompareRule) ErrorObject(err Error) FieldCompareRule {
+	r.err = err
+	return r
+}
+
+// RequiredWith returns a validation rule that checks if a value is not empty when any of the other fields is not empty.
+// The other fields must be specified as pointers to fields of the struct being validated by ValidateStruct,
+// and their error names are given as the "fields" param of the error. For example,
+//
+//	kv.Field(&a.Zip, kv.RequiredWith(&a.Street, &a.City))
+//
+// Please refer to Required for how a value is considered empty.
+func RequiredWith(others ...any) FieldPresenceRule {
+	return FieldPresenceRule{others: others, mode: requiredWith, err: ErrRequiredWith}
+}
+
+// RequiredWithout returns a validation rule that checks if a value is not empty when any of the other fields is empty.
+// Please refer to RequiredWith for how to specify the other fields.
+func RequiredWithout(others ...any) FieldPresenceRule {
+	return FieldPresenceRule{others: others, mode: requiredWithout, err: ErrRequiredWithout}
+}
+
+// ExcludedWith returns a validation rule that checks if a value is empty when any of the other fields is not empty.
+// Please refer to RequiredWith for how to specify the other fields.
+func ExcludedWith(others ...any) FieldPresenceRule {
+	return FieldPresenceRule{others: others, mode: excludedWith, err: ErrExcludedWith}
+}
+
+// Validate checks if the given value is valid or not.
+// The other field is looked up in the struct that ValidateStruct makes available through the context,
+// so an InternalError wrapping ErrFieldReference is returned when the rule is validated on its own.
+func (r FieldPresenceRule) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r FieldPresenceRule) ValidateWithContext(ctx context.Context, value any) error {
+	value, isNil := Indirect(value)
+	empty := isNil || IsEmpty(value)
+	if empty == (r.mode == excludedWith) {
+		// an excluded value is valid when empty, a required one when present
+		return nil
+	}
+
+	names := make([]string, 0, len(r.others))
+	triggered := false
+	for _, ptr := range r.others {
+		name, other, err := referencedField(ctx, ptr)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		other, isNil := Indirect(other)
+		if (isNil || IsEmpty(other)) == (r.mode == requiredWithout) {
+			triggered = true
+		}
+	}
+	if !triggered {
+		return nil
+	}
+	return r.err.SetParams(map[string]any{"fields": strings.Join(names, ", ")})
+}
+
+// Error sets the error message for the rule.
+func (r FieldPresenceRule) Error(message string) FieldPresenceRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r FieldPresenceRule) ErrorObject(err Error) FieldPresenceRule {
+	r.err = err
+	return r
+}
+
+// withStruct returns a copy of the context carrying the struct being validated,
+// so that the fields referenced by rules can be found. A nil context is replaced by context.TODO().
+func withStruct(ctx context.Context, structPtr any) context.Context {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return context.WithValue(ctx, structKey{}, structPtr)
+}
+
+// referencedField returns the error name and the value of the field that ptr points to.
+// The field must belong to the struct being validated.
+func referencedField(ctx context.Context, ptr any) (string, any, error) {
+	var structValue reflect.Value
+	if ctx != nil {
+		structValue = reflect.ValueOf(ctx.Value(structKey{}))
+	}
+	fv := reflect.ValueOf(ptr)
+	if structValue.Kind() != reflect.Ptr || fv.Kind() != reflect.Ptr || fv.IsNil() {
+		return "", nil, NewInternalError(ErrFieldReference)
+	}
+	f := findStructField(structValue.Elem(), fv)
+	if f == nil {
+		return "", nil, NewInternalError(ErrFieldReference)
+	}
+	return getErrorFieldName(f), fv.Elem().Interface(), nil
+}
+
+// compareEqual compares two values for equality. It returns 0 if they are equal.
+func compareEqual(value, other any) (int, error) {
+	if t, ok := value.(time.Time); ok {
+		if o, ok := other.(time.Time); ok && t.Equal(o) {
+			return 0, nil
+		}
+		return 1, nil
+	}
+	if reflect.DeepEqual(value, other) {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func compareOrdered[T Ordered](value, other any) (int, error) {
+	v, err := toType[T](value)
+	if err != nil {
+		return 0, err
+	}
+	o, err := toType[T](other)
+	if err != nil {
+		return 0, err
+	}
+	return cmp.Compare(v, o), nil
+}
+
+func compareTime(value, other any) (int, error) {
+	v, err := toType[time.Time](value)
+	if err != nil {
+		return 0, err
+	}
+	o, err := toType[time.Time](other)
+	if err != nil {
+		return 0, err
+	}
+	return v.Compare(o), nil
+}
+
+func (r FieldCompareRule) skipsEmpty() bool {
+	return true
+}
+
+func (r FieldCompareRule) referencesFields() bool {
+	return true
+}
+
+func (r FieldPresenceRule) referencesFields() bool {
+	return true
+}
+
+// ruleReferencesFields checks if the rule references other fields of the struct being validated.
+func ruleReferencesFields(rule any) bool {
+	f, ok := unwrapRule(rule).(fieldReferrer)
+	return ok && f.referencesFields()
+}
+
+// rulesReferenceFields checks if any of the rules references other fields of the struct being validated.
+func rulesReferenceFields[R any](rules []R) bool {
+	for _, rule := range rules {
+		if ruleReferencesFields(rule) {
+			return true
+		}
+	}
+	return false
+}
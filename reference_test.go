@@ -0,0 +1,160 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type referenceModel struct {
+	Password string `json:"password"`
+	Confirm  string `json:"confirm"`
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	Start    time.Time
+	End      *time.Time
+	Street   string `json:"street"`
+	City     string `json:"city"`
+	Zip      string `json:"zip"`
+	Tags     []string
+}
+
+func TestFieldCompareRule(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t3 := t1.In(time.FixedZone("X", 3600))
+
+	var m referenceModel
+	tests := []struct {
+		tag   string
+		model referenceModel
+		field func(m *referenceModel) *FieldRules
+		err   string
+	}{
+		{"t1.1", referenceModel{Password: "abc", Confirm: "abc"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, EqualTo(&m.Password)) }, ""},
+		{"t1.2", referenceModel{Password: "abc", Confirm: "xyz"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, EqualTo(&m.Password)) }, "confirm: must be equal to password."},
+		{"t1.3", referenceModel{Password: "abc"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, EqualTo(&m.Password)) }, ""},
+		{"t1.4", referenceModel{Confirm: "abc"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, EqualTo(&m.Password)) }, "confirm: must be equal to password."},
+		{"t1.5", referenceModel{Start: t1, End: &t3}, func(m *referenceModel) *FieldRules { return Field(&m.End, EqualTo(&m.Start)) }, ""},
+		{"t1.6", referenceModel{Tags: []string{"a"}}, func(m *referenceModel) *FieldRules { return Field(&m.Tags, EqualTo(&m.Tags)) }, ""},
+		{"t2.1", referenceModel{Password: "abc", Confirm: "abc"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, NotEqualTo(&m.Password)) }, "confirm: must not be equal to password."},
+		{"t2.2", referenceModel{Password: "abc", Confirm: "xyz"}, func(m *referenceModel) *FieldRules { return Field(&m.Confirm, NotEqualTo(&m.Password)) }, ""},
+		{"t3.1", referenceModel{Min: 1, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, GreaterThan(&m.Min)) }, ""},
+		{"t3.2", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, GreaterThan(&m.Min)) }, "max: must be greater than min."},
+		{"t3.3", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, GreaterThan(&m.Min).OrEqual()) }, ""},
+		{"t3.4", referenceModel{Min: 3, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, GreaterThan(&m.Min).OrEqual()) }, "max: must be no less than min."},
+		{"t3.5", referenceModel{Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, LessThan(&m.Min)) }, ""},
+		{"t3.6", referenceModel{Min: 3}, func(m *referenceModel) *FieldRules { return Field(&m.Max, LessThan(&m.Min)) }, ""},
+		{"t3.7", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, LessThan(&m.Min)) }, "max: must be less than min."},
+		{"t3.8", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, LessThan(&m.Min).OrEqual()) }, ""},
+		{"t3.9", referenceModel{Min: 1, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, LessThan(&m.Min).OrEqual()) }, "max: must be no greater than min."},
+		{"t3.10", referenceModel{Password: "a", Min: 1}, func(m *referenceModel) *FieldRules { return Field(&m.Password, GreaterThan(&m.Min)) }, "password: must be a value of type int."},
+		{"t4.1", referenceModel{Start: t1, End: &t2}, func(m *referenceModel) *FieldRules { return Field(&m.End, GreaterThanTime(&m.Start)) }, ""},
+		{"t4.2", referenceModel{Start: t2, End: &t1}, func(m *referenceModel) *FieldRules { return Field(&m.End, GreaterThanTime(&m.Start)) }, "End: must be greater than Start."},
+		{"t4.3", referenceModel{Start: t1, End: &t3}, func(m *referenceModel) *FieldRules { return Field(&m.End, GreaterThanTime(&m.Start).OrEqual()) }, ""},
+		{"t4.4", referenceModel{Start: t1, End: &t2}, func(m *referenceModel) *FieldRules { return Field(&m.End, LessThanTime(&m.Start)) }, "End: must be less than Start."},
+		{"t4.5", referenceModel{Start: t2}, func(m *referenceModel) *FieldRules { return Field(&m.End, GreaterThanTime(&m.Start)) }, ""},
+		// custom errors
+		{"t5.1", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules {
+			return Field(&m.Max, GreaterThan(&m.Min).Error("must exceed {{.field}}"))
+		}, "max: must exceed min."},
+		{"t5.2", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules {
+			return Field(&m.Max, GreaterThan(&m.Min).ErrorObject(NewError("code", "bad {{.field}}")))
+		}, "max: bad min."},
+		// nested in other rules
+		{"t6.1", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, When(true, GreaterThan(&m.Min))) }, "max: must be greater than min."},
+		// invalid references
+		{"t7.1", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, GreaterThan(new(int))) }, ErrFieldReference.Error()},
+		{"t7.2", referenceModel{Min: 2, Max: 2}, func(m *referenceModel) *FieldRules { return Field(&m.Max, EqualTo(m.Min)) }, ErrFieldReference.Error()},
+	}
+	for _, test := range tests {
+		m = test.model
+		err := ValidateStruct(&m, test.field(&m))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// the rules cannot be used outside of ValidateStruct
+	err := Validate(2, GreaterThan(&m.Min))
+	if ie, ok := err.(InternalError); assert.True(t, ok) {
+		assert.Equal(t, ErrFieldReference, ie.InternalError())
+	}
+	_, ok := ValidateWithContext(context.Background(), 2, EqualTo(nil)).(InternalError)
+	assert.True(t, ok)
+
+	// codes and params
+	m = referenceModel{Min: 2, Max: 2}
+	err = ValidateStruct(&m, Field(&m.Max, GreaterThan(&m.Min)))
+	var e Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, ErrGreaterThanField.Code(), e.Code())
+		assert.Equal(t, map[string]any{"field": "min"}, e.Params())
+	}
+
+	// translation
+	err = ValidateStructWithContext(WithLocale(context.Background(), "fr"), &m, Field(&m.Max, GreaterThan(&m.Min)))
+	assert.EqualError(t, err, "max: doit être supérieur à min.")
+}
+
+func TestFieldPresenceRule(t *testing.T) {
+	var m referenceModel
+	tests := []struct {
+		tag   string
+		model referenceModel
+		rule  func(m *referenceModel) Rule[any]
+		err   string
+	}{
+		{"t1.1", referenceModel{}, func(m *referenceModel) Rule[any] { return RequiredWith(&m.Street, &m.City) }, ""},
+		{"t1.2", referenceModel{City: "x"}, func(m *referenceModel) Rule[any] { return RequiredWith(&m.Street, &m.City) }, "zip: cannot be blank when street, city is present."},
+		{"t1.3", referenceModel{City: "x", Zip: "1"}, func(m *referenceModel) Rule[any] { return RequiredWith(&m.Street, &m.City) }, ""},
+		{"t2.1", referenceModel{Street: "x", City: "x"}, func(m *referenceModel) Rule[any] { return RequiredWithout(&m.Street, &m.City) }, ""},
+		{"t2.2", referenceModel{Street: "x"}, func(m *referenceModel) Rule[any] { return RequiredWithout(&m.Street, &m.City) }, "zip: cannot be blank when street, city is missing."},
+		{"t2.3", referenceModel{Zip: "1"}, func(m *referenceModel) Rule[any] { return RequiredWithout(&m.Street, &m.City) }, ""},
+		{"t3.1", referenceModel{Zip: "1"}, func(m *referenceModel) Rule[any] { return ExcludedWith(&m.Street) }, ""},
+		{"t3.2", referenceModel{Zip: "1", Street: "x"}, func(m *referenceModel) Rule[any] { return ExcludedWith(&m.Street) }, "zip: must be blank when street is present."},
+		{"t3.3", referenceModel{Street: "x"}, func(m *referenceModel) Rule[any] { return ExcludedWith(&m.Street) }, ""},
+		{"t4.1", referenceModel{City: "x"}, func(m *referenceModel) Rule[any] { return RequiredWith(&m.City).Error("zip is needed") }, "zip: zip is needed."},
+		{"t4.2", referenceModel{City: "x"}, func(m *referenceModel) Rule[any] { return RequiredWith(&m.City).ErrorObject(NewError("c", "needed")) }, "zip: needed."},
+		{"t5.1", referenceModel{City: "x"}, func(m *referenceModel) Rule[any] { return RequiredWith(m.City) }, ErrFieldReference.Error()},
+	}
+	for _, test := range tests {
+		m = test.model
+		err := ValidateStruct(&m, Field(&m.Zip, test.rule(&m)))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	_, ok := Validate("", RequiredWith(&m.City)).(InternalError)
+	assert.True(t, ok)
+}
+
+func TestValidateStructReferences(t *testing.T) {
+	nonEmpty := func(value any) bool { return value != "" }
+	m := referenceModel{Password: "abc", Confirm: "xyz", City: "x"}
+
+	// a nil context does not prevent the referenced fields from being found
+	err := ValidateStructWithContext(nil, &m, Field(&m.Confirm, EqualTo(&m.Password)))
+	assert.EqualError(t, err, "confirm: must be equal to password.")
+
+	// the rules and conditions nested in other rules are found
+	tests := []struct {
+		tag  string
+		rule Rule[any]
+		err  string
+	}{
+		{"t1", When(true, RequiredWith(&m.City)), "zip: cannot be blank when city is present."},
+		{"t2", WhenField(&m.City, nonEmpty, Required), "zip: cannot be blank."},
+		{"t3", Required.WhenField(&m.City, nonEmpty), "zip: cannot be blank."},
+		{"t4", Skip.WhenField(&m.City, nonEmpty), ""},
+		{"t5", Not[any](RequiredWith(&m.City)), ""},
+		{"t6", AnyOf[any](ExcludedWith(&m.City), Required), ""},
+		{"t7", AllOf[any](Length(0, 5), RequiredWith(&m.City)), "zip: cannot be blank when city is present."},
+		{"t8", Warn[any](RequiredWith(&m.City)), ""},
+		{"t9", Any(Typed[string](RequiredWith(&m.City))), "zip: cannot be blank when city is present."},
+	}
+	for _, test := range tests {
+		err := ValidateStruct(&m, Field(&m.Zip, test.rule, Length(0, 5)))
+		assertError(t, test.err, err, test.tag)
+	}
+}
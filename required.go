@@ -4,6 +4,8 @@
 
 package kv
 
+import "context"
+
 var (
 	// ErrRequired is the error that returns when a value is required.
 	ErrRequired = NewError("validation_required", "cannot be blank")
@@ -18,22 +20,31 @@ var (
 // - string, array, slice, map: len() > 0
 // - interface, pointer: not nil and the referenced value is not empty
 // - any other types
-var Required = RequiredRule{skipNil: false, condition: true}
+var Required = RequiredRule{skipNil: false, condition: fixedCondition(true)}
 
 // NilOrNotEmpty checks if a value is a nil pointer or a value that is not empty.
 // NilOrNotEmpty differs from Required in that it treats a nil pointer as valid.
-var NilOrNotEmpty = RequiredRule{skipNil: true, condition: true}
+var NilOrNotEmpty = RequiredRule{skipNil: true, condition: fixedCondition(true)}
 
 // RequiredRule is a rule that checks if a value is not empty.
 type RequiredRule struct {
-	condition bool
+	condition condition
 	skipNil   bool
 	err       Error
 }
 
 // Validate checks if the given value is valid or not.
 func (r RequiredRule) Validate(value any) error {
-	if r.condition {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r RequiredRule) ValidateWithContext(ctx context.Context, value any) error {
+	met, err := r.condition.met(ctx, value)
+	if err != nil {
+		return err
+	}
+	if met {
 		value, isNil := Indirect(value)
 		if r.skipNil && !isNil && IsEmpty(value) || !r.skipNil && (isNil || IsEmpty(value)) {
 			if r.err != nil {
@@ -50,10 +61,36 @@ func (r RequiredRule) Validate(value any) error {
 
 // When sets the condition that determines if the validation should be performed.
 func (r RequiredRule) When(condition bool) RequiredRule {
-	r.condition = condition
+	r.condition = fixedCondition(condition)
 	return r
 }
 
+// WhenFunc sets the function that determines if the validation should be performed.
+// The function is called each time a value is validated, with the context of the validation.
+// Please refer to the WhenFunc function for the details.
+func (r RequiredRule) WhenFunc(f func(ctx context.Context) bool) RequiredRule {
+	r.condition = funcCondition(f)
+	return r
+}
+
+// WhenValue sets the function that determines if the validation should be performed for the value being validated.
+func (r RequiredRule) WhenValue(f func(value any) bool) RequiredRule {
+	r.condition = valueCondition(f)
+	return r
+}
+
+// WhenField sets the function that determines if the validation should be performed for the value of another field.
+// Please refer to the WhenField function for the details.
+func (r RequiredRule) WhenField(fieldPtr any, f func(value any) bool) RequiredRule {
+	r.condition = fieldCondition(fieldPtr, f)
+	return r
+}
+
+func (r RequiredRule) conditionFalse(ctx context.Context, value any) bool {
+	met, err := r.condition.met(ctx, value)
+	return err == nil && !met
+}
+
 // Error sets the error message for the rule.
 func (r RequiredRule) Error(message string) RequiredRule {
 	if r.err == nil {
@@ -72,3 +109,7 @@ func (r RequiredRule) ErrorObject(err Error) RequiredRule {
 	r.err = err
 	return r
 }
+
+func (r RequiredRule) referencesFields() bool {
+	return r.condition.field
+}
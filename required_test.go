@@ -5,6 +5,7 @@
 package kv
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -98,3 +99,25 @@ func TestRequiredRule_Error(t *testing.T) {
 	assert.Equal(t, err.Message(), r.err.Message())
 	assert.NotEqual(t, err, Required.err)
 }
+
+func TestRequiredRule_WhenFunc(t *testing.T) {
+	r := Required.WhenFunc(func(ctx context.Context) bool { return ctx.Value(roleKey{}) == "admin" })
+	assert.Nil(t, Validate(nil, r))
+	err := ValidateWithContext(context.WithValue(context.Background(), roleKey{}, "admin"), nil, r)
+	assert.Equal(t, ErrRequired, err)
+
+	r = NilOrNotEmpty.WhenValue(func(value any) bool { return value != "" })
+	assert.Nil(t, r.Validate(""))
+
+	type user struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	}
+	u := user{}
+	noEmail := func(value any) bool { return value == "" }
+	err = ValidateStruct(&u, Field(&u.Phone, Required.WhenField(&u.Email, noEmail)))
+	assert.EqualError(t, err, "phone: cannot be blank.")
+	u.Email = "a@example.com"
+	assert.Nil(t, ValidateStruct(&u, Field(&u.Phone, Required.WhenField(&u.Email, noEmail))))
+	assert.EqualError(t, Required.WhenField(&u.Email, noEmail).Validate(""), ErrFieldReference.Error())
+}
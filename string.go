@@ -62,3 +62,7 @@ func (r StringRule) Validate(value any) error {
 
 	return r.err
 }
+
+func (r StringRule) skipsEmpty() bool {
+	return true
+}
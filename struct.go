@@ -26,8 +26,9 @@ type (
 
 	// FieldRules represents a rule set associated with a struct field.
 	FieldRules struct {
-		fieldPtr any
-		rules    []Rule[any]
+		fieldPtr   any
+		rules      []Rule[any]
+		collectAll bool
 	}
 )
 
@@ -65,7 +66,8 @@ func ValidateStruct(structPtr any, fields ...*FieldRules) error {
 
 // ValidateStructWithContext validates a struct with the given context.
 // The only difference between ValidateStructWithContext and ValidateStruct is that the former will
-// validate struct fields with the provided context.
+// validate struct fields with the provided context. If the context carries a field mask set by WithFieldMask,
+// the fields absent from the mask are not validated.
 // Please refer to ValidateStruct for the detailed instructions on how to use this function.
 func ValidateStructWithContext(ctx context.Context, structPtr any, fields ...*FieldRules) error {
 	value := reflect.ValueOf(structPtr)
@@ -78,8 +80,16 @@ func ValidateStructWithContext(ctx context.Context, structPtr any, fields ...*Fi
 		return nil
 	}
 	value = value.Elem()
+	for _, fr := range fields {
+		if rulesReferenceFields(fr.rules) {
+			// make the struct available to the rules referencing other fields
+			ctx = withStruct(ctx, structPtr)
+			break
+		}
+	}
 
 	errs := Errors{}
+	mask, track := FieldMaskFromContext(ctx), tracksPath(ctx)
 
 	for i, fr := range fields {
 		fv := reflect.ValueOf(fr.fieldPtr)
@@ -90,11 +100,30 @@ func ValidateStructWithContext(ctx context.Context, structPtr any, fields ...*Fi
 		if ft == nil {
 			return NewInternalError(ErrFieldNotFound(i))
 		}
+		fctx, name := ctx, ""
+		if !ft.Anonymous && (mask != nil || track) {
+			name = getErrorFieldName(ft)
+			var present bool
+			if fctx, present = maskField(ctx, mask, name); !present {
+				// skip the fields absent from the field mask
+				continue
+			}
+			if track {
+				fctx = withPathElement(fctx, name)
+			}
+		}
 		var err error
-		if ctx == nil {
+		switch {
+		case hasTransformer(fr.rules):
+			err = validateTransformedField(fctx, fv, fr.rules, fr.collectAll)
+		case fr.collectAll && fctx == nil:
+			err = ValidateAll(fv.Elem().Interface(), fr.rules...)
+		case fr.collectAll:
+			err = validateAllWithContext(fctx, fv.Elem().Interface(), fr.rules)
+		case fctx == nil:
 			err = Validate(fv.Elem().Interface(), fr.rules...)
-		} else {
-			err = ValidateWithContext(ctx, fv.Elem().Interface(), fr.rules...)
+		default:
+			err = validateWithContext(fctx, fv.Elem().Interface(), fr.rules...)
 		}
 		if err != nil {
 			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
@@ -109,12 +138,15 @@ func ValidateStructWithContext(ctx context.Context, structPtr any, fields ...*Fi
 					continue
 				}
 			}
-			errs[getErrorFieldName(ft)] = err
+			if name == "" {
+				name = getErrorFieldName(ft)
+			}
+			errs[name] = err
 		}
 	}
 
 	if len(errs) > 0 {
-		return errs
+		return Translate(ctx, errs)
 	}
 	return nil
 }
@@ -128,9 +160,17 @@ func Field(fieldPtr any, rules ...Rule[any]) *FieldRules {
 	}
 }
 
+// CollectAll configures the field to be validated by all of its rules, instead of stopping at the first failing rule.
+// The errors of the failing rules are reported as an ErrorList. Please refer to ValidateAll for the details.
+func (r *FieldRules) CollectAll() *FieldRules {
+	r.collectAll = true
+	return r
+}
+
 // findStructField looks for a field in the given struct.
 // The field being looked for should be a pointer to the actual struct field.
-// If found, the field info will be returned. Otherwise, nil will be returned.
+// If found, the field info will be returned, with the index sequence of the field in the given struct as its Index,
+// so that the same field can be found in another value of the struct. Otherwise, nil will be returned.
 func findStructField(structValue reflect.Value, fieldValue reflect.Value) *reflect.StructField {
 	ptr := fieldValue.Pointer()
 	for i := structValue.NumField() - 1; i >= 0; i-- {
@@ -150,6 +190,7 @@ func findStructField(structValue reflect.Value, fieldValue reflect.Value) *refle
 			}
 			if fi.Kind() == reflect.Struct {
 				if f := findStructField(fi, fieldValue); f != nil {
+					f.Index = append([]int{i}, f.Index...)
 					return f
 				}
 			}
@@ -161,8 +202,8 @@ func findStructField(structValue reflect.Value, fieldValue reflect.Value) *refle
 // getErrorFieldName returns the name that should be used to represent the validation error of a struct field.
 func getErrorFieldName(f *reflect.StructField) string {
 	if tag := f.Tag.Get(ErrorTag); tag != "" && tag != "-" {
-		if cps := strings.SplitN(tag, ",", 2); cps[0] != "" {
-			return cps[0]
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
 		}
 	}
 	return f.Name
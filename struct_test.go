@@ -122,6 +122,11 @@ func TestValidateStruct(t *testing.T) {
 		{"t8.8", &m3, []*FieldRules{Field(&m4.A, Required)}, "field #0 cannot be found in the struct"},
 		// internal error
 		{"t9.1", &m5, []*FieldRules{Field(&m5.A, &validateAbc{}), Field(&m5.B, Required), Field(&m5.A, &validateInternalError{})}, "error internal"},
+		{"t9.2", &m5, []*FieldRules{Field(&m5.A, &validateInternalError{}, &validateXyz{}).CollectAll()}, "error internal"},
+		// collect all errors
+		{"t10.1", &m1, []*FieldRules{Field(&m1.A, &validateXyz{}, Length(5, 10)).CollectAll(), Field(&m1.B, &validateAbc{}, Length(5, 10))}, "A: error xyz, the length must be between 5 and 10; B: error abc."},
+		{"t10.2", &m1, []*FieldRules{Field(&m1.A, &validateXyz{}, Skip, Length(5, 10)).CollectAll()}, "A: error xyz."},
+		{"t10.3", &m2, []*FieldRules{Field(&m2.E).CollectAll()}, "E: error 123."},
 	}
 	for _, test := range tests {
 		err1 := ValidateStruct(test.model, test.rules...)
@@ -0,0 +1,225 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OutcomePassed is the outcome of a rule that validated a value successfully.
+	OutcomePassed Outcome = "passed"
+	// OutcomeFailed is the outcome of a rule that returned an error.
+	OutcomeFailed Outcome = "failed"
+	// OutcomeSkipped is the outcome of a rule that did not check the value. The reason is given by TraceEntry.Reason.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+const (
+	reasonSkip      = "skipped by a preceding Skip rule"
+	reasonCondition = "condition is false"
+	reasonNil       = "nil value"
+	reasonEmpty     = "empty value"
+	reasonTransform = "transform"
+)
+
+type (
+	// Outcome is the outcome of a rule run recorded by Trace.
+	Outcome string
+
+	// Trace records the rules run by a validation started by WithTrace. It is safe for concurrent use.
+	// Trace can be printed, or marshaled into a JSON array of its entries.
+	Trace struct {
+		mu      sync.Mutex
+		entries []TraceEntry
+	}
+
+	// TraceEntry is a rule run recorded by Trace.
+	TraceEntry struct {
+		// Path is the path of the value validated by the rule.
+		Path Path `json:"path"`
+		// Key tells if the rule validated the key of the map entry located by Path rather than its value.
+		Key bool `json:"key,omitempty"`
+		// Rule is the type of the rule, such as "kv.LengthRule".
+		Rule string `json:"rule"`
+		// Outcome is the outcome of the rule.
+		Outcome Outcome `json:"outcome"`
+		// Reason explains why the rule was skipped, or is "transform" for a transform rule.
+		Reason string `json:"reason,omitempty"`
+		// Error is the message of the error returned by the rule.
+		Error string `json:"error,omitempty"`
+		// Duration is the time spent running the rule, including the rules nested in it.
+		Duration time.Duration `json:"duration"`
+	}
+
+	// adapter is implemented by the rules adapting another rule, such as those returned by Any and Typed.
+	adapter interface {
+		adapted() any
+	}
+
+	// conditionalRule is implemented by rules that can be turned off by a condition, such as WhenRule and RequiredRule.
+	conditionalRule interface {
+		// conditionFalse checks if the rule is turned off for the value, so that it does not check it.
+		conditionFalse(ctx context.Context, value any) bool
+	}
+
+	// emptySkipper is implemented by rules that pass nil and empty values without checking them, such as LengthRule,
+	// so that the trace reports them as skipped. Rules checking nil or empty values, such as RequiredRule, do not.
+	emptySkipper interface {
+		skipsEmpty() bool
+	}
+
+	traceKey struct{}
+)
+
+// WithTrace returns a copy of the context that traces the validation. When a value is validated with the returned
+// context, every rule run is recorded in the returned Trace with the path of the value, the type of the rule,
+// its outcome and its duration. For example,
+//
+//	ctx, trace := kv.WithTrace(ctx)
+//	err := kv.ValidateWithContext(ctx, &customer)
+//	fmt.Println(trace)
+//	// name kv.RequiredRule passed 1.2µs
+//	// email kv.LengthRule skipped (empty value) 310ns
+//
+// A rule is reported as skipped if it follows a Skip rule, if it is turned off by a condition, such as When(false),
+// or if it passes a nil or empty value without checking it, as Length and Match do. Rules checking nil or empty values,
+// such as Required and NilOrNotEmpty, are reported as passed.
+// The rules nested in other rules, such as those of When and Each, are recorded as well, after the rule containing them.
+// Tracing has no effect on the validation result.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// Entries returns the rule runs recorded so far, in the order they were completed.
+func (t *Trace) Entries() []TraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.entries)
+}
+
+// String returns the recorded rule runs, one per line.
+func (t *Trace) String() string {
+	var s strings.Builder
+	for _, e := range t.Entries() {
+		s.WriteString(e.String())
+		s.WriteByte('\n')
+	}
+	return s.String()
+}
+
+// MarshalJSON marshals the recorded rule runs into a JSON array.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	entries := t.Entries()
+	if entries == nil {
+		entries = []TraceEntry{}
+	}
+	return json.Marshal(entries)
+}
+
+// String returns the entry in the form of "path rule outcome (reason): error duration".
+// The path of a rule validating a map key is followed by "(key)".
+func (e TraceEntry) String() string {
+	path := e.Path.String()
+	if path == "" {
+		path = "."
+	}
+	if e.Key {
+		path += " (key)"
+	}
+	s := path + " " + e.Rule + " " + string(e.Outcome)
+	if e.Reason != "" {
+		s += " (" + e.Reason + ")"
+	}
+	if e.Error != "" {
+		s += ": " + e.Error
+	}
+	return s + " " + e.Duration.String()
+}
+
+// traceFromContext returns the Trace of a validation started by WithTrace, if any.
+func traceFromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+// record records the run of a rule started at the given time, which validated the value and returned err.
+func (t *Trace) record(ctx context.Context, rule, value any, start time.Time, err error) {
+	rule = unwrapRule(rule)
+	e := newTraceEntry(ctx, rule)
+	e.Duration = time.Since(start)
+	if err != nil {
+		e.Outcome, e.Error = OutcomeFailed, err.Error()
+	} else if c, ok := rule.(conditionalRule); ok && c.conditionFalse(ctx, value) {
+		e.Outcome, e.Reason = OutcomeSkipped, reasonCondition
+	} else {
+		e.Outcome = OutcomePassed
+		if s, ok := rule.(emptySkipper); ok && s.skipsEmpty() {
+			if value, isNil := Indirect(value); isNil {
+				e.Outcome, e.Reason = OutcomeSkipped, reasonNil
+			} else if IsEmpty(value) {
+				e.Outcome, e.Reason = OutcomeSkipped, reasonEmpty
+			}
+		}
+	}
+	t.add(e)
+}
+
+// recordTransform records the run of a transform rule started at the given time.
+func (t *Trace) recordTransform(ctx context.Context, rule any, start time.Time, err error) {
+	e := newTraceEntry(ctx, rule)
+	e.Outcome, e.Reason, e.Duration = OutcomePassed, reasonTransform, time.Since(start)
+	if err != nil {
+		e.Outcome, e.Error = OutcomeFailed, err.Error()
+	}
+	t.add(e)
+}
+
+func (t *Trace) add(e TraceEntry) {
+	t.mu.Lock()
+	t.entries = append(t.entries, e)
+	t.mu.Unlock()
+}
+
+// traceSkipped records the rules following a Skip rule as skipped.
+func traceSkipped[T any](ctx context.Context, t *Trace, rules []Rule[T]) {
+	for _, rule := range rules {
+		e := newTraceEntry(ctx, rule)
+		e.Outcome, e.Reason = OutcomeSkipped, reasonSkip
+		t.add(e)
+	}
+}
+
+// newTraceEntry returns the entry of a rule run on the value located by the context.
+func newTraceEntry(ctx context.Context, rule any) TraceEntry {
+	return TraceEntry{Path: slices.Clone(pathFromContext(ctx)), Key: isKeyPath(ctx), Rule: ruleName(rule)}
+}
+
+// unwrapRule returns the rule adapted by Any or Typed, if any.
+func unwrapRule(rule any) any {
+	for {
+		a, ok := rule.(adapter)
+		if !ok {
+			return rule
+		}
+		rule = a.adapted()
+	}
+}
+
+// ruleName returns the name of the type of a rule, without the pointer indirection and the adapters.
+func ruleName(rule any) string {
+	rule = unwrapRule(rule)
+	if rt := reflect.TypeOf(rule); rt != nil && rt.Kind() == reflect.Ptr {
+		return rt.Elem().String()
+	}
+	return fmt.Sprintf("%T", rule)
+}
@@ -0,0 +1,118 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type traceProfile struct {
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Nick  *string  `json:"nick"`
+	Tags  []string `json:"tags"`
+	Age   int      `json:"age"`
+}
+
+func traceSummary(t *Trace) []any {
+	var summary []any
+	for _, e := range t.Entries() {
+		s := e.Path.String() + " " + e.Rule + " " + string(e.Outcome)
+		if e.Reason != "" {
+			s += " (" + e.Reason + ")"
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+func TestTrace(t *testing.T) {
+	ctx, trace := WithTrace(context.Background())
+	p := traceProfile{Name: " john ", Tags: []string{"a", "toolong"}, Age: 10}
+	err := ValidateStructWithContext(ctx, &p,
+		Field(&p.Name, TrimSpace, Required, Length(2, 10)),
+		Field(&p.Email, Length(5, 50), When(false, Required)),
+		Field(&p.Nick, Skip, Required),
+		Field(&p.Tags, Each(Length(1, 3))),
+		Field(&p.Age, Required.When(false), Any(Min(18))),
+	)
+	assert.EqualError(t, err, "age: must be no less than 18; tags: (1: the length must be between 1 and 3.).")
+	assert.Equal(t, []any{
+		"name kv.TransformRule passed (transform)",
+		"name kv.RequiredRule passed",
+		"name kv.LengthRule passed",
+		"email kv.LengthRule skipped (empty value)",
+		"email kv.WhenRule skipped (condition is false)",
+		"nick kv.RequiredRule skipped (skipped by a preceding Skip rule)",
+		"tags[0] kv.LengthRule passed",
+		"tags[1] kv.LengthRule failed",
+		"tags kv.EachRule failed",
+		"age kv.RequiredRule skipped (condition is false)",
+		"age kv.ThresholdRule[int] failed",
+	}, traceSummary(trace))
+
+	entries := trace.Entries()
+	assert.Equal(t, "the length must be between 1 and 3", entries[7].Error)
+	assert.True(t, entries[6].Duration >= 0)
+
+	// printing and serializing
+	lines := strings.Split(strings.TrimSpace(trace.String()), "\n")
+	assert.Equal(t, len(entries), len(lines))
+	assert.True(t, strings.HasPrefix(lines[3], "email kv.LengthRule skipped (empty value) "), lines[3])
+	assert.True(t, strings.HasPrefix(lines[7], "tags[1] kv.LengthRule failed: the length must be between 1 and 3 "), lines[7])
+	b, err := json.Marshal(trace)
+	assert.NoError(t, err)
+	var decoded []map[string]any
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, len(entries), len(decoded))
+	assert.Equal(t, "skipped", decoded[3]["outcome"])
+	assert.Equal(t, "empty value", decoded[3]["reason"])
+	assert.Equal(t, []any{"email"}, decoded[3]["path"])
+
+	b, err = json.Marshal(&Trace{})
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(b))
+}
+
+func TestTraceTyped(t *testing.T) {
+	ctx, trace := WithTrace(context.Background())
+	rules := Struct[traceProfile](
+		FieldOf("age", func(p *traceProfile) *int { return &p.Age }, Min(18), Max(150)),
+		FieldOf("tags", func(p *traceProfile) *[]string { return &p.Tags }, EachOf(Typed[string](Required))),
+	)
+	p := traceProfile{Age: 20, Tags: []string{""}}
+	assert.EqualError(t, rules.ValidateWithContext(ctx, &p), "tags: (0: cannot be blank.).")
+	assert.Equal(t, []any{
+		"age kv.ThresholdRule[int] passed",
+		"age kv.ThresholdRule[int] passed",
+		"tags[0] kv.RequiredRule failed",
+		"tags kv.EachOfRule[string] failed",
+	}, traceSummary(trace))
+
+	// nil values and ValidateAll
+	ctx, trace = WithTrace(context.Background())
+	assert.NoError(t, ValidateAllWithContext(ctx, (*string)(nil), Length(1, 3), NilOrNotEmpty, Skip, Required))
+	assert.Equal(t, []any{
+		" kv.LengthRule skipped (nil value)",
+		" kv.RequiredRule passed",
+		" kv.RequiredRule skipped (skipped by a preceding Skip rule)",
+	}, traceSummary(trace))
+	assert.True(t, strings.HasPrefix(trace.String(), ". kv.LengthRule skipped (nil value) "))
+
+	// only the rules that do not check empty values are skipped
+	ctx, trace = WithTrace(context.Background())
+	assert.NoError(t, ValidateValueWithContext(ctx, 0, Min(1), Not(Typed[int](In(0)))))
+	assert.EqualError(t, ValidateWithContext(ctx, "", Length(5, 10), Required), "cannot be blank")
+	assert.Equal(t, []any{
+		" kv.ThresholdRule[int] skipped (empty value)",
+		" kv.NotRule[int] skipped (empty value)",
+		" kv.LengthRule skipped (empty value)",
+		" kv.RequiredRule failed",
+	}, traceSummary(trace))
+
+	// no trace
+	assert.NoError(t, ValidateWithContext(context.Background(), "abc", Length(1, 3)))
+}
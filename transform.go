@@ -0,0 +1,269 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrTransformType is the error that the value cannot be modified by a transform rule because of its type.
+var ErrTransformType = errors.New("the value cannot be transformed because of its type")
+
+var (
+	// TrimSpace is a transform rule that removes the leading and trailing white space of a string.
+	TrimSpace = stringTransform(strings.TrimSpace)
+	// Lower is a transform rule that maps a string to lower case.
+	Lower = stringTransform(strings.ToLower)
+	// Upper is a transform rule that maps a string to upper case.
+	Upper = stringTransform(strings.ToUpper)
+	// NFC is a transform rule that normalizes a string to the Unicode Normalization Form C.
+	NFC = stringTransform(norm.NFC.String)
+	// CollapseSpace is a transform rule that replaces each run of white space in a string with a single space,
+	// and removes the leading and trailing white space.
+	CollapseSpace = stringTransform(func(s string) string { return strings.Join(strings.Fields(s), " ") })
+)
+
+type (
+	// Transformer is implemented by rules that modify the value being validated before the following rules run.
+	// Transform receives a pointer to the value.
+	Transformer interface {
+		Transform(ptr any) error
+	}
+
+	// TransformRule is a rule that modifies the value of a struct field before the following rules run.
+	// The field is modified through the pointer given to Field, so the rules listed before a transform rule
+	// validate the original value, and the rules listed after it validate the modified one. For example,
+	//
+	//	kv.Field(&u.Email, kv.TrimSpace, kv.Lower, kv.Required, is.Email)
+	//
+	// Transform rules have no effect when a value is validated by Validate or ValidateValue,
+	// because the value cannot be modified without a pointer to it.
+	TransformRule struct {
+		transform func(ptr any) error
+	}
+)
+
+// Transform returns a transform rule that modifies a value of type T with the given function.
+// The value must be exactly of type T, or a pointer to it. If the function returns an error, the error is reported for the field and the following rules are not run.
+// For example,
+//
+//	kv.Field(&p.Slug, kv.Transform(func(s *string) error {
+//	    *s = strings.ReplaceAll(*s, " ", "-")
+//	    return nil
+//	}))
+func Transform[T any](f func(*T) error) TransformRule {
+	return TransformRule{transform: func(ptr any) error {
+		if p, ok := ptr.(*T); ok {
+			return f(p)
+		}
+		v, err := indirectPtr(ptr, reflect.TypeFor[T]())
+		if err != nil || !v.IsValid() {
+			return err
+		}
+		p, ok := v.Addr().Interface().(*T)
+		if !ok {
+			return NewInternalError(ErrTransformType)
+		}
+		return f(p)
+	}}
+}
+
+// Clamp returns a transform rule that limits a number or a string to the range between min and max.
+// A value less than min is set to min, and a value greater than max is set to max.
+func Clamp[T Ordered](min, max T) TransformRule {
+	return TransformRule{transform: func(ptr any) error {
+		v, err := indirectPtr(ptr, reflect.TypeFor[T]())
+		if err != nil || !v.IsValid() {
+			return err
+		}
+		t := reflect.TypeFor[T]()
+		value := v.Convert(t).Interface().(T)
+		if value < min {
+			value = min
+		} else if value > max {
+			value = max
+		}
+		v.Set(reflect.ValueOf(value).Convert(v.Type()))
+		return nil
+	}}
+}
+
+// Validate does nothing, because a value cannot be modified without a pointer to it.
+func (r TransformRule) Validate(any) error {
+	return nil
+}
+
+// Transform modifies the value that the given pointer points to.
+func (r TransformRule) Transform(ptr any) error {
+	return r.transform(ptr)
+}
+
+// stringTransform returns a transform rule that modifies a string with the given function.
+func stringTransform(f func(string) string) TransformRule {
+	return TransformRule{transform: func(ptr any) error {
+		if p, ok := ptr.(*string); ok {
+			*p = f(*p)
+			return nil
+		}
+		v, err := indirectPtr(ptr, reflect.TypeFor[string]())
+		if err != nil || !v.IsValid() {
+			return err
+		}
+		v.SetString(f(v.String()))
+		return nil
+	}}
+}
+
+// indirectPtr returns the settable value that ptr points to, following nested pointers.
+// The kind of the value must be the same as the kind of t, and if t is not a basic type, the types must be equal.
+// An invalid value is returned if a nil pointer is met.
+func indirectPtr(ptr any, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr {
+		return reflect.Value{}, NewInternalError(ErrTransformType)
+	}
+	for v.Kind() == reflect.Ptr && v.Type().Elem() != t {
+		if v.IsNil() {
+			return reflect.Value{}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() != t && (v.Kind() != t.Kind() || t.PkgPath() != "" || !v.CanConvert(t)) {
+		return reflect.Value{}, NewInternalError(ErrTransformType)
+	}
+	return v, nil
+}
+
+// transformerOf returns the rule as a Transformer, looking through the adapters returned by Any and Typed.
+func transformerOf(rule any) (Transformer, bool) {
+	switch r := rule.(type) {
+	case Transformer:
+		return r, true
+	case interface{ transformer() (Transformer, bool) }:
+		return r.transformer()
+	}
+	return nil, false
+}
+
+// transformWithContext applies the transform rule to the value that ptr points to, passing the context along
+// if the rule is context-aware.
+func transformWithContext(ctx context.Context, t Transformer, ptr any) error {
+	if tc, ok := t.(TransformerWithContext); ok && ctx != nil {
+		return tc.TransformWithContext(ctx, ptr)
+	}
+	return t.Transform(ptr)
+}
+
+// hasTransformer checks if any of the rules is a Transformer.
+func hasTransformer[T any](rules []Rule[T]) bool {
+	for _, rule := range rules {
+		if _, ok := transformerOf(rule); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTransformedField runs the rules of a struct field in order, applying the transform rules to the field
+// through its pointer, so that the rules following a transform rule validate the modified value.
+// The errors are collected the same way as validateAll does when collectAll is true.
+func validateTransformedField(ctx context.Context, fieldPtr reflect.Value, rules []Rule[any], collectAll bool) error {
+	if defaultsPlanFromContext(ctx) != nil {
+		// transform a copy of the field during a dry run
+		ptr := reflect.New(fieldPtr.Type().Elem())
+		ptr.Elem().Set(fieldPtr.Elem())
+		fieldPtr = ptr
+	}
+	var errs ErrorList
+	trace := traceFromContext(ctx)
+	for i, rule := range rules {
+		if s, ok := rule.(skipper[any]); ok && s.skipped(ctx, fieldPtr.Elem().Interface()) {
+			if trace != nil {
+				traceSkipped(ctx, trace, rules[i+1:])
+			}
+			return errs.Filter()
+		}
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		var err error
+		if t, ok := transformerOf(rule); ok {
+			err = transformWithContext(ctx, t, fieldPtr.Interface())
+			if trace != nil {
+				trace.recordTransform(ctx, rule, start, err)
+			}
+		} else {
+			value := fieldPtr.Elem().Interface()
+			err = validateRule(ctx, rule, value)
+			if trace != nil {
+				trace.record(ctx, rule, value, start, err)
+			}
+		}
+		if err == nil {
+			continue
+		}
+		if !collectAll || isInternalError(err) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	if err := errs.Filter(); err != nil {
+		return err
+	}
+	if ctx == nil {
+		return Validate(fieldPtr.Elem().Interface())
+	}
+	return validateWithContext(ctx, fieldPtr.Elem().Interface())
+}
+
+// validateTransformedValue is the typed counterpart of validateTransformedField.
+func validateTransformedValue[T any](ctx context.Context, ptr *T, rules []Rule[T]) error {
+	if defaultsPlanFromContext(ctx) != nil {
+		// transform a copy of the field during a dry run
+		v := *ptr
+		ptr = &v
+	}
+	trace := traceFromContext(ctx)
+	for i, rule := range rules {
+		if s, ok := rule.(skipper[T]); ok && s.skipped(ctx, *ptr) {
+			if trace != nil {
+				traceSkipped(ctx, trace, rules[i+1:])
+			}
+			return nil
+		}
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		var err error
+		if t, ok := transformerOf(rule); ok {
+			err = transformWithContext(ctx, t, ptr)
+			if trace != nil {
+				trace.recordTransform(ctx, rule, start, err)
+			}
+		} else {
+			err = validateRule(ctx, rule, *ptr)
+			if trace != nil {
+				trace.record(ctx, rule, *ptr, start, err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if ctx == nil {
+		return ValidateValue(*ptr)
+	}
+	return validateValueWithContext(ctx, *ptr)
+}
@@ -0,0 +1,174 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type transformModel struct {
+	Name    string
+	Email   *string
+	Slug    slug
+	Age     int
+	Score   float64
+	Comment string
+}
+
+type slug string
+
+func TestStringTransforms(t *testing.T) {
+	tests := []struct {
+		tag      string
+		rule     TransformRule
+		value    string
+		expected string
+	}{
+		{"t1", TrimSpace, "  a b  ", "a b"},
+		{"t2", Lower, "ABc", "abc"},
+		{"t3", Upper, "abC", "ABC"},
+		{"t4", NFC, "é", "é"},
+		{"t5", CollapseSpace, " a \t\n b  c ", "a b c"},
+	}
+	for _, test := range tests {
+		s := test.value
+		assert.NoError(t, test.rule.Transform(&s), test.tag)
+		assert.Equal(t, test.expected, s, test.tag)
+
+		// named types and nested pointers
+		n := slug(test.value)
+		assert.NoError(t, test.rule.Transform(&n), test.tag)
+		assert.Equal(t, slug(test.expected), n, test.tag)
+		p := &s
+		s = test.value
+		assert.NoError(t, test.rule.Transform(&p), test.tag)
+		assert.Equal(t, test.expected, s, test.tag)
+	}
+
+	var p *string
+	assert.NoError(t, TrimSpace.Transform(&p))
+	assert.Nil(t, p)
+	assert.NoError(t, TrimSpace.Validate(" a "))
+
+	err := TrimSpace.Transform(new(int))
+	assert.Equal(t, ErrTransformType, err.(InternalError).InternalError())
+	err = TrimSpace.Transform("abc")
+	assert.Equal(t, ErrTransformType, err.(InternalError).InternalError())
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		tag      string
+		value    int
+		expected int
+	}{
+		{"t1", -5, 0},
+		{"t2", 5, 5},
+		{"t3", 105, 100},
+	}
+	for _, test := range tests {
+		v := test.value
+		assert.NoError(t, Clamp(0, 100).Transform(&v), test.tag)
+		assert.Equal(t, test.expected, v, test.tag)
+	}
+
+	type level int
+	l := level(9)
+	assert.NoError(t, Clamp(1, 5).Transform(&l))
+	assert.Equal(t, level(5), l)
+
+	f := 1.5
+	assert.NoError(t, Clamp(0.0, 1.0).Transform(&f))
+	assert.Equal(t, 1.0, f)
+
+	err := Clamp(0, 100).Transform(new(string))
+	assert.Equal(t, ErrTransformType, err.(InternalError).InternalError())
+}
+
+func TestTransform(t *testing.T) {
+	replace := Transform(func(s *string) error {
+		*s = strings.ReplaceAll(*s, " ", "-")
+		return nil
+	})
+	s := "a b c"
+	assert.NoError(t, replace.Transform(&s))
+	assert.Equal(t, "a-b-c", s)
+
+	fail := Transform(func(*string) error { return errors.New("cannot transform") })
+	assert.EqualError(t, fail.Transform(&s), "cannot transform")
+
+	n := slug("a b")
+	err := replace.Transform(&n)
+	assert.Equal(t, ErrTransformType, err.(InternalError).InternalError())
+}
+
+func TestValidateStructWithTransforms(t *testing.T) {
+	email := "  John@Example.COM "
+	m := transformModel{Name: "  ", Email: &email, Slug: "Hello World", Age: 150, Score: -1, Comment: " a   b "}
+	err := ValidateStruct(&m,
+		Field(&m.Name, TrimSpace, Required),
+		Field(&m.Email, TrimSpace, Lower, Match(regexp.MustCompile(`^[a-z]+@[a-z.]+$`))),
+		Field(&m.Slug, Lower, Transform(func(s *slug) error {
+			*s = slug(strings.ReplaceAll(string(*s), " ", "-"))
+			return nil
+		})),
+		Field(&m.Age, Clamp(0, 130)),
+		Field(&m.Score, Clamp(0.0, 10.0)),
+		// rules before a transform see the original value
+		Field(&m.Comment, Length(0, 5), CollapseSpace),
+	)
+	assert.EqualError(t, err, "Comment: the length must be no more than 5; Name: cannot be blank.")
+	assert.Equal(t, "", m.Name)
+	assert.Equal(t, "john@example.com", email)
+	assert.Equal(t, slug("hello-world"), m.Slug)
+	assert.Equal(t, 130, m.Age)
+	assert.Equal(t, 0.0, m.Score)
+	assert.Equal(t, " a   b ", m.Comment)
+
+	// collect all errors
+	m = transformModel{Name: " abcdef "}
+	err = ValidateStruct(&m, Field(&m.Name, Length(0, 3), TrimSpace, Length(0, 4), Upper).CollectAll())
+	assert.EqualError(t, err, "Name: the length must be no more than 3, the length must be no more than 4.")
+	assert.Equal(t, "ABCDEF", m.Name)
+
+	// skip stops the transforms
+	m = transformModel{Name: " a "}
+	assert.NoError(t, ValidateStruct(&m, Field(&m.Name, Skip, TrimSpace)))
+	assert.Equal(t, " a ", m.Name)
+
+	// transform errors
+	m = transformModel{}
+	err = ValidateStruct(&m, Field(&m.Name, Transform(func(*string) error { return NewError("code", "bad") })))
+	assert.EqualError(t, err, "Name: bad.")
+	err = ValidateStruct(&m, Field(&m.Age, TrimSpace))
+	assert.Equal(t, ErrTransformType, err.(InternalError).InternalError())
+
+	// adapted rules and context
+	m = transformModel{Name: " abc "}
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	err = ValidateStructWithContext(ctx, &m, Field(&m.Name, Any(Typed[string](TrimSpace)), WithContext(func(ctx context.Context, value any) error {
+		if value != ctx.Value(contains) {
+			return errors.New("unexpected value")
+		}
+		return nil
+	})))
+	assert.NoError(t, err)
+}
+
+func TestStructRulesWithTransforms(t *testing.T) {
+	rules := Struct[transformModel](
+		FieldOf("name", func(m *transformModel) *string { return &m.Name }, Typed[string](TrimSpace), Typed[string](Required)),
+		FieldOf("age", func(m *transformModel) *int { return &m.Age }, Typed[int](Clamp(0, 130)), Max(120)),
+	)
+	m := transformModel{Name: " a ", Age: 200}
+	assert.EqualError(t, rules.Validate(&m), "age: must be no greater than 120.")
+	assert.Equal(t, "a", m.Name)
+	assert.Equal(t, 130, m.Age)
+
+	m = transformModel{Name: "   "}
+	assert.EqualError(t, rules.ValidateWithContext(context.Background(), &m), "name: cannot be blank.")
+}
@@ -0,0 +1,150 @@
+package kv
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+type (
+	// Translator is the interface for looking up the translations of validation error messages.
+	Translator interface {
+		// Translate returns the message template of the error in the given locale.
+		// A false value is returned if there is no translation for the error.
+		Translate(locale string, err Error) (string, bool)
+	}
+
+	// Catalog is a Translator that maps error codes to message templates for each locale.
+	// The message templates use the same text/template syntax as the error messages, and are
+	// executed with the error params. A Catalog is safe for concurrent use.
+	Catalog struct {
+		mu       sync.RWMutex
+		messages map[string]map[string]string
+	}
+
+	localeKey     struct{}
+	translatorKey struct{}
+)
+
+// DefaultCatalog is the Translator used to localize validation errors when the context carries no other Translator.
+// It contains the Arabic ("ar") and French ("fr") translations of the errors returned by the built-in rules.
+var DefaultCatalog = newDefaultCatalog()
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[string]string{}}
+}
+
+// Add adds the message templates of a locale, indexed by error code, to the catalog.
+// Existing messages of the locale with the same codes are replaced.
+// An error is returned if a message is not a valid template.
+func (c *Catalog) Add(locale string, messages map[string]string) error {
+	for _, message := range messages {
+		if _, err := template.New("err").Parse(message); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m, ok := c.messages[locale]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		c.messages[locale] = m
+	}
+	for code, message := range messages {
+		m[code] = message
+	}
+	return nil
+}
+
+// Translate returns the message template for the code of the error in the given locale.
+// If the locale has a region (e.g. "fr-CA") and no message is found for it, the base language ("fr") is used.
+func (c *Catalog) Translate(locale string, err Error) (string, bool) {
+	code := err.Code()
+	if code == "" {
+		return "", false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for {
+		if message, ok := c.messages[locale][code]; ok {
+			return message, true
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// WithLocale returns a copy of the context carrying the locale in which validation errors should be reported.
+// ValidateWithContext and the other context-aware validation functions translate the errors into the locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale carried by the context, or an empty string if there is none.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// WithTranslator returns a copy of the context carrying the Translator used instead of DefaultCatalog.
+func WithTranslator(ctx context.Context, translator Translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, translator)
+}
+
+// Translate translates the messages of the given validation error, and of the errors nested in it,
+// into the locale carried by the context. The errors are returned unchanged if the context carries no locale.
+// Errors without a translation, errors whose message was customized by SetMessage, such as by Required.Error("..."),
+// and errors that do not implement Error, are kept as they are.
+func Translate(ctx context.Context, err error) error {
+	if err == nil || ctx == nil {
+		return err
+	}
+	locale := LocaleFromContext(ctx)
+	if locale == "" {
+		return err
+	}
+	translator, ok := ctx.Value(translatorKey{}).(Translator)
+	if !ok {
+		translator = DefaultCatalog
+	}
+	return translate(translator, locale, err)
+}
+
+func translate(translator Translator, locale string, err error) error {
+	switch e := err.(type) {
+	case Errors:
+		es := make(Errors, len(e))
+		for key, value := range e {
+			if value != nil {
+				value = translate(translator, locale, value)
+			}
+			es[key] = value
+		}
+		return es
+	case ErrorList:
+		es := make(ErrorList, len(e))
+		for i, value := range e {
+			es[i] = translate(translator, locale, value)
+		}
+		return es
+	case KeyError:
+		return KeyError{Err: translate(translator, locale, e.Err)}
+	case InternalError:
+		return err
+	case Error:
+		if c, ok := e.(interface{ customMessage() bool }); ok && c.customMessage() {
+			return err
+		}
+		if message, ok := translator.Translate(locale, e); ok {
+			return e.SetMessage(message)
+		}
+	}
+	return err
+}
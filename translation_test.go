@@ -0,0 +1,203 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	c := NewCatalog()
+	assert.NoError(t, c.Add("fr", map[string]string{
+		"code_a": "message a",
+		"code_b": "message b {{.min}}",
+	}))
+	assert.NoError(t, c.Add("fr-CA", map[string]string{
+		"code_a": "message a (CA)",
+	}))
+	assert.NotNil(t, c.Add("fr", map[string]string{"code_c": "{{.min"}))
+
+	tests := []struct {
+		tag     string
+		locale  string
+		code    string
+		message string
+		ok      bool
+	}{
+		{"t1", "fr", "code_a", "message a", true},
+		{"t2", "fr", "code_b", "message b {{.min}}", true},
+		{"t3", "fr-CA", "code_a", "message a (CA)", true},
+		{"t4", "fr-CA", "code_b", "message b {{.min}}", true},
+		{"t5", "fr_BE", "code_b", "message b {{.min}}", true},
+		{"t6", "fr", "code_c", "", false},
+		{"t7", "ar", "code_a", "", false},
+		{"t8", "fr", "", "", false},
+	}
+	for _, test := range tests {
+		message, ok := c.Translate(test.locale, NewError(test.code, "default"))
+		assert.Equal(t, test.message, message, test.tag)
+		assert.Equal(t, test.ok, ok, test.tag)
+	}
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	errs := []Error{
+		ErrNil, ErrEmpty, ErrDateInvalid, ErrDateOutOfRange, ErrInInvalid,
+		ErrLengthTooLong, ErrLengthTooShort, ErrLengthInvalid, ErrLengthOutOfRange, ErrLengthEmptyRequired,
+		ErrKeyWrongType, ErrKeyMissing, ErrKeyUnexpected, ErrMatchInvalid,
+		ErrMinGreaterEqualThanRequired, ErrMaxLessEqualThanRequired, ErrMinGreaterThanRequired, ErrMaxLessThanRequired,
+		ErrMultipleOfInvalid, ErrNotInInvalid, ErrNotNilRequired, ErrRequired, ErrNilOrNotEmpty, ErrTypeMismatch,
+		ErrEqualToField, ErrNotEqualToField, ErrGreaterThanField, ErrGreaterEqualThanField, ErrLessThanField, ErrLessEqualThanField,
+		ErrRequiredWith, ErrRequiredWithout, ErrExcludedWith,
+		ErrUnique, ErrMinItems, ErrMaxItems, ErrContains, ErrItemsIn, ErrSortedAscending, ErrSortedDescending,
+		ErrMapTooFewKeys, ErrMapTooManyKeys, ErrKeyDependent,
+		ErrDiscriminatorInvalid, ErrJSONObjectInvalid, ErrOneOfNone, ErrOneOfMultiple,
+		ErrAnyOf, ErrNot,
+		ErrImmutable, ErrOneWay, ErrTransition, ErrIncreasing, ErrDecreasing,
+	}
+	for _, locale := range []string{"ar", "fr"} {
+		for _, err := range errs {
+			_, ok := DefaultCatalog.Translate(locale, err)
+			assert.True(t, ok, locale+": "+err.Code())
+		}
+	}
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	assert.Equal(t, "", LocaleFromContext(context.Background()))
+	assert.Equal(t, "fr", LocaleFromContext(WithLocale(context.Background(), "fr")))
+}
+
+func TestTranslate(t *testing.T) {
+	ctx := WithLocale(context.Background(), "fr")
+	internal := NewInternalError(errors.New("internal"))
+	tests := []struct {
+		tag string
+		ctx context.Context
+		err error
+		msg string
+	}{
+		{"t1", ctx, nil, ""},
+		{"t2", ctx, ErrRequired, "ne peut pas être vide"},
+		{"t3", ctx, Length(2, 5).Validate("a"), "la longueur doit être comprise entre 2 et 5"},
+		{"t4", ctx, errors.New("plain"), "plain"},
+		{"t5", ctx, internal, "internal"},
+		{"t6", ctx, NewError("unknown", "unknown code"), "unknown code"},
+		{"t7", ctx, Errors{"a": ErrRequired, "b": Errors{"c": ErrNotNilRequired}}, "a: ne peut pas être vide; b: (c: est obligatoire.)."},
+		{"t8", context.Background(), ErrRequired, "cannot be blank"},
+		{"t9", nil, ErrRequired, "cannot be blank"},
+		{"t10", ctx, ErrorList{ErrRequired, errors.New("plain")}, "ne peut pas être vide, plain"},
+		{"t11", WithLocale(context.Background(), "ar-EG"), ErrRequired, "لا يمكن أن يكون فارغًا"},
+	}
+	for _, test := range tests {
+		err := Translate(test.ctx, test.err)
+		assertError(t, test.msg, err, test.tag)
+	}
+
+	// the code is kept
+	err := Translate(ctx, ErrRequired)
+	if e, ok := err.(Error); assert.True(t, ok) {
+		assert.Equal(t, ErrRequired.Code(), e.Code())
+	}
+	// internal errors are kept
+	_, ok := Translate(ctx, internal).(InternalError)
+	assert.True(t, ok)
+}
+
+func TestValidateWithLocale(t *testing.T) {
+	ctx := WithLocale(context.Background(), "fr")
+
+	err := ValidateWithContext(ctx, "", Required)
+	assert.EqualError(t, err, "ne peut pas être vide")
+	err = ValidateWithContext(ctx, 20, Any(Max(10)))
+	assert.EqualError(t, err, "doit être inférieur ou égal à 10")
+	err = ValidateValueWithContext(ctx, 20, Max(10))
+	assert.EqualError(t, err, "doit être inférieur ou égal à 10")
+	err = ValidateWithContext(ctx, String123("abc"))
+	assert.EqualError(t, err, "error 123")
+
+	a := struct {
+		Name  string
+		Value string
+	}{"", "demo"}
+	err = ValidateStructWithContext(ctx, &a,
+		Field(&a.Name, Required),
+		Field(&a.Value, Required, Length(5, 10)),
+	)
+	assert.EqualError(t, err, "Name: ne peut pas être vide; Value: la longueur doit être comprise entre 5 et 10.")
+
+	// without a locale
+	err = ValidateWithContext(context.Background(), "", Required)
+	assert.EqualError(t, err, "cannot be blank")
+}
+
+func TestWithTranslator(t *testing.T) {
+	c := NewCatalog()
+	assert.NoError(t, c.Add("en-GB", map[string]string{ErrRequired.Code(): "must not be left blank"}))
+	ctx := WithTranslator(WithLocale(context.Background(), "en-GB"), c)
+
+	assert.EqualError(t, ValidateWithContext(ctx, "", Required), "must not be left blank")
+	assert.EqualError(t, ValidateWithContext(ctx, "abc", Length(1, 2)), "the length must be between 1 and 2")
+}
+
+func TestTranslateCustomMessage(t *testing.T) {
+	ctx := WithLocale(context.Background(), "fr")
+	assert.EqualError(t, ValidateWithContext(ctx, "", Required.Error("custom")), "custom")
+	assert.EqualError(t, ValidateWithContext(ctx, "", Required.ErrorObject(ErrRequired.SetMessage("custom"))), "custom")
+	assert.EqualError(t, ValidateWithContext(ctx, "", Required.ErrorObject(ErrNotNilRequired)), "est obligatoire")
+	assert.EqualError(t, ValidateValueWithContext(ctx, 20, Max(10).Error("too big")), "too big")
+
+	// errors created while validating are translated whatever their message
+	fr := NewCatalog()
+	assert.NoError(t, fr.Add("fr", map[string]string{"too_big": "doit être inférieur à {{.max}}"}))
+	ctx = WithTranslator(ctx, fr)
+	for _, n := range []int{10, 20} {
+		rule := By(func(any) error {
+			return NewError("too_big", fmt.Sprintf("must be less than %d", n)).SetParams(map[string]any{"max": n})
+		})
+		assert.EqualError(t, ValidateWithContext(ctx, 30, rule), fmt.Sprintf("doit être inférieur à %d", n))
+	}
+}
+
+type countingTranslator struct {
+	calls int
+}
+
+func (c *countingTranslator) Translate(locale string, err Error) (string, bool) {
+	c.calls++
+	return DefaultCatalog.Translate(locale, err)
+}
+
+func TestTranslateOnce(t *testing.T) {
+	translator := &countingTranslator{}
+	ctx := WithTranslator(WithLocale(context.Background(), "fr"), translator)
+	a := struct {
+		Tags  []string
+		Value string
+	}{[]string{"", "ok", ""}, "a"}
+	err := ValidateStructWithContext(ctx, &a,
+		Field(&a.Tags, When(true, Each(Required))),
+		Field(&a.Value, When(true, Length(5, 10))),
+	)
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, translator.calls)
+
+	type inner struct{ Age int }
+	type outer struct {
+		Tags  []string
+		Inner *inner
+	}
+	translator.calls = 0
+	rules := Struct[outer](
+		FieldOf("tags", func(o *outer) *[]string { return &o.Tags }, EachOf(Typed[string](Required))),
+		FieldOf("inner", func(o *outer) **inner { return &o.Inner }, Struct[inner](
+			FieldOf("age", func(i *inner) *int { return &i.Age }, Min(18)),
+		)),
+	)
+	err = rules.ValidateWithContext(ctx, &outer{Tags: []string{""}, Inner: &inner{Age: 10}})
+	assert.EqualError(t, err, "inner: (age: doit être supérieur ou égal à 18.); tags: (0: ne peut pas être vide.).")
+	assert.Equal(t, 2, translator.calls)
+}
@@ -0,0 +1,229 @@
+package kv
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+type (
+	// EachOfRule is a validation rule that validates the elements of a slice using the specified list of rules.
+	EachOfRule[E any] struct {
+		rules []Rule[E]
+	}
+
+	// EachEntryRule is a validation rule that validates the keys and values of a map using the specified lists of rules.
+	EachEntryRule[K comparable, V any] struct {
+		keyRules   []Rule[K]
+		valueRules []Rule[V]
+	}
+
+	// EachSeqRule is a validation rule that validates the values of a sequence using the specified list of rules.
+	EachSeqRule[E any] struct {
+		rules []Rule[E]
+	}
+
+	// EachSeq2Rule is a validation rule that validates the pairs of a sequence using the specified lists of rules.
+	EachSeq2Rule[K, V any] struct {
+		keyRules   []Rule[K]
+		valueRules []Rule[V]
+	}
+)
+
+// EachOf returns a validation rule that validates each element of a slice with the provided rules.
+// Unlike Each, the elements are validated by rules written for their type, without reflection.
+// The errors are indexed by the element indices. An empty slice is considered valid.
+func EachOf[E any](rules ...Rule[E]) EachOfRule[E] {
+	return EachOfRule[E]{rules: rules}
+}
+
+// Validate validates each element of the slice.
+func (r EachOfRule[E]) Validate(value []E) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext validates each element of the slice with the given context.
+func (r EachOfRule[E]) ValidateWithContext(ctx context.Context, value []E) error {
+	return validateEntries(ctx, slices.All(value), nil, r.rules, strconv.Itoa)
+}
+
+// EachEntry returns a validation rule that validates each key and value of a map with the provided rules.
+// For example,
+//
+//	kv.EachEntry(
+//	    []kv.Rule[string]{kv.Typed[string](kv.Match(labelKey))},
+//	    []kv.Rule[string]{kv.Typed[string](kv.Length(0, 63))},
+//	)
+//
+// The errors are indexed by the keys formatted with fmt. The error of a key is reported as a KeyError, so that
+// it is told apart from the error of its value, and both are merged into an ErrorList if the key and the value
+// are invalid. An empty map is considered valid.
+func EachEntry[K comparable, V any](keyRules []Rule[K], valueRules []Rule[V]) EachEntryRule[K, V] {
+	return EachEntryRule[K, V]{keyRules: keyRules, valueRules: valueRules}
+}
+
+// Validate validates each entry of the map.
+func (r EachEntryRule[K, V]) Validate(value map[K]V) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext validates each entry of the map with the given context.
+func (r EachEntryRule[K, V]) ValidateWithContext(ctx context.Context, value map[K]V) error {
+	return validateEntries(ctx, maps.All(value), r.keyRules, r.valueRules, formatKey[K])
+}
+
+// EachSeq returns a validation rule that validates each value of a sequence with the provided rules.
+// The errors are indexed by the positions of the values in the sequence. An empty sequence is considered valid.
+func EachSeq[E any](rules ...Rule[E]) EachSeqRule[E] {
+	return EachSeqRule[E]{rules: rules}
+}
+
+// Validate validates each value of the sequence.
+func (r EachSeqRule[E]) Validate(value iter.Seq[E]) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext validates each value of the sequence with the given context.
+func (r EachSeqRule[E]) ValidateWithContext(ctx context.Context, value iter.Seq[E]) error {
+	if value == nil {
+		return nil
+	}
+	indexed := func(yield func(int, E) bool) {
+		i := 0
+		for v := range value {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+	return validateEntries(ctx, indexed, nil, r.rules, strconv.Itoa)
+}
+
+// EachSeq2 returns a validation rule that validates each pair of a sequence with the provided rules.
+// The errors are indexed by the keys of the pairs formatted with fmt, and the errors of pairs with
+// the same key are merged. Please refer to EachEntry for the details.
+func EachSeq2[K, V any](keyRules []Rule[K], valueRules []Rule[V]) EachSeq2Rule[K, V] {
+	return EachSeq2Rule[K, V]{keyRules: keyRules, valueRules: valueRules}
+}
+
+// Validate validates each pair of the sequence.
+func (r EachSeq2Rule[K, V]) Validate(value iter.Seq2[K, V]) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext validates each pair of the sequence with the given context.
+func (r EachSeq2Rule[K, V]) ValidateWithContext(ctx context.Context, value iter.Seq2[K, V]) error {
+	if value == nil {
+		return nil
+	}
+	return validateEntries(ctx, value, r.keyRules, r.valueRules, formatKey[K])
+}
+
+// validateEntries validates the keys and values of a sequence, and indexes the errors by the names of the keys.
+// The errors of a key are wrapped in a KeyError. An InternalError stops the validation and is returned immediately.
+func validateEntries[K, V any](ctx context.Context, seq iter.Seq2[K, V], keyRules []Rule[K], valueRules []Rule[V], name func(K) string) error {
+	var errs Errors
+	track := tracksPath(ctx)
+	for k, v := range seq {
+		if len(keyRules) > 0 {
+			kctx := ctx
+			if track {
+				kctx = withKeyPathElement(ctx, name(k))
+			}
+			kerr := validateValueWithContext(kctx, k, keyRules...)
+			if isInternalError(kerr) {
+				return kerr
+			}
+			if kerr != nil {
+				errs = addEntryError(errs, name(k), KeyError{Err: kerr})
+			}
+		}
+		vctx := ctx
+		if track {
+			vctx = withPathElement(ctx, name(k))
+		}
+		verr := validateValueWithContext(vctx, v, valueRules...)
+		if isInternalError(verr) {
+			return verr
+		}
+		if verr != nil {
+			errs = addEntryError(errs, name(k), verr)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// addEntryError adds the error of an entry to the errors, merging it with the errors of the entries of the same name.
+func addEntryError(errs Errors, name string, err error) Errors {
+	if errs == nil {
+		errs = Errors{}
+	}
+	errs[name] = joinErrors(errs[name], err)
+	return errs
+}
+
+// isInternalError checks if the error is an InternalError.
+func isInternalError(err error) bool {
+	ie, ok := err.(InternalError)
+	return ok && ie.InternalError() != nil
+}
+
+// joinErrors merges two errors of the same value into an ErrorList.
+func joinErrors(err1, err2 error) error {
+	switch {
+	case err1 == nil:
+		return err2
+	case err2 == nil:
+		return err1
+	}
+	if list, ok := err1.(ErrorList); ok {
+		return append(list, err2)
+	}
+	return ErrorList{err1, err2}
+}
+
+// formatKey returns the name that should be used to represent the validation error of a map key.
+func formatKey[K any](key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+	return getErrorKeyName(key)
+}
+
+func (r EachOfRule[E]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}}
+}
+
+func (r EachOfRule[E]) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
+
+func (r EachEntryRule[K, V]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.keyRules)}, {rules: anyRules(r.valueRules)}}
+}
+
+func (r EachSeqRule[E]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}}
+}
+
+func (r EachSeq2Rule[K, V]) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.keyRules)}, {rules: anyRules(r.valueRules)}}
+}
+
+func (r EachEntryRule[K, V]) referencesFields() bool {
+	return rulesReferenceFields(r.keyRules) || rulesReferenceFields(r.valueRules)
+}
+
+func (r EachSeqRule[E]) referencesFields() bool {
+	return rulesReferenceFields(r.rules)
+}
+
+func (r EachSeq2Rule[K, V]) referencesFields() bool {
+	return rulesReferenceFields(r.keyRules) || rulesReferenceFields(r.valueRules)
+}
@@ -0,0 +1,181 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestEachOf(t *testing.T) {
+	rule := EachOf(Min(1), Max(10))
+	tests := []struct {
+		tag   string
+		value []int
+		err   string
+	}{
+		{"t1", nil, ""},
+		{"t2", []int{1, 5, 10}, ""},
+		{"t3", []int{-1, 5, 20}, "0: must be no less than 1; 2: must be no greater than 10."},
+		{"t4", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, "10: must be no greater than 10."},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+		assertError(t, test.err, ValidateValue(test.value, rule), test.tag)
+	}
+
+	// with legacy rules
+	err := Validate([]string{"a", ""}, Any(EachOf(Typed[string](Required))))
+	assert.EqualError(t, err, "1: cannot be blank.")
+
+	// with context
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	err = ValidateValueWithContext(ctx, []string{"abc", "xyz"}, EachOf[string](typedContainsRule{}))
+	assert.EqualError(t, err, "1: unexpected value.")
+
+	// internal errors
+	err = EachOf(Typed[string](&validateInternalError{})).Validate([]string{"abc", "internal"})
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestEachEntry(t *testing.T) {
+	keyRules := []Rule[string]{Typed[string](Length(1, 5)), Typed[string](&validateAbc{})}
+	valueRules := []Rule[int]{Max(10)}
+	rule := EachEntry(keyRules, valueRules)
+	tests := []struct {
+		tag   string
+		value map[string]int
+		err   string
+	}{
+		{"t1", nil, ""},
+		{"t2", map[string]int{"abc": 1, "abcd": 10}, ""},
+		{"t3", map[string]int{"abc": 11}, "abc: must be no greater than 10."},
+		{"t4", map[string]int{"xyz": 1}, "xyz: key: error abc."},
+		{"t5", map[string]int{"abcdef": 11}, "abcdef: key: the length must be between 1 and 5, must be no greater than 10."},
+		{"t6", map[string]int{"abc (key)": 11, "abc": 11}, "abc: must be no greater than 10; abc (key): key: the length must be between 1 and 5, must be no greater than 10."},
+	}
+	for _, test := range tests {
+		assertError(t, test.err, rule.Validate(test.value), test.tag)
+	}
+
+	// the errors of a key are reported apart from the errors of its value
+	err := EachEntry(keyRules, valueRules).Validate(map[string]int{"abcdef": 11})
+	if es, ok := err.(Errors); assert.True(t, ok) {
+		assert.Equal(t, 1, len(es))
+		assert.True(t, errors.Is(es["abcdef"], ErrMaxLessEqualThanRequired))
+		var ke KeyError
+		assert.True(t, errors.As(es["abcdef"], &ke))
+		assert.True(t, errors.Is(ke, ErrLengthOutOfRange))
+
+		flat := es.Flatten()
+		assert.Equal(t, 2, len(flat))
+		assert.Equal(t, "/abcdef", flat[0].Path.Pointer())
+		assert.True(t, flat[0].Key)
+		assert.Equal(t, "the length must be between 1 and 5", flat[0].Message)
+		assert.Equal(t, "/abcdef", flat[1].Path.Pointer())
+		assert.False(t, flat[1].Key)
+
+		// the key errors survive the JSON formats holding the codes
+		for _, format := range []JSONFormat{JSONObjects, JSONFlat} {
+			b, err := es.MarshalJSONFormat(format)
+			assert.NoError(t, err)
+			var decoded Errors
+			assert.NoError(t, json.Unmarshal(b, &decoded))
+			assert.True(t, errors.As(decoded["abcdef"], &ke), format)
+			assert.Equal(t, es.Error(), decoded.Error(), format)
+		}
+	}
+	ctx, trace := WithTrace(context.Background())
+	_ = EachEntry(keyRules, valueRules).ValidateWithContext(ctx, map[string]int{"abcdef": 11})
+	var failed []any
+	for _, e := range trace.Entries() {
+		if e.Outcome == OutcomeFailed {
+			failed = append(failed, e.Path.String(), e.Key)
+		}
+	}
+	assert.Equal(t, []any{"abcdef", true, "abcdef", false}, failed)
+
+	// the key errors are translated and reported as warnings
+	ctx, warnings := CollectWarnings(WithLocale(context.Background(), "fr"))
+	err = ValidateValueWithContext(ctx, map[string]int{"abcdef": 1}, EachEntry[string, int]([]Rule[string]{Warn(Typed[string](Length(1, 5)))}, nil))
+	assert.NoError(t, err)
+	if list := warnings.List(); assert.Equal(t, 1, len(list)) {
+		assert.True(t, list[0].Key)
+		assert.Equal(t, "abcdef", list[0].Path.String())
+	}
+	err = ValidateValueWithContext(ctx, map[string]int{"abcdef": 1}, EachEntry(keyRules, valueRules))
+	assert.EqualError(t, err, "abcdef: key: la longueur doit être comprise entre 1 et 5.")
+
+	// non-string keys
+	err = EachEntry([]Rule[int]{Min(0)}, []Rule[string]{Typed[string](Required)}).Validate(map[int]string{-1: "a", 2: "", 3: "c"})
+	assert.EqualError(t, err, "-1: key: must be no less than 0; 2: cannot be blank.")
+	err = EachEntry[bool](nil, []Rule[string]{Typed[string](Required)}).Validate(map[bool]string{true: ""})
+	assert.EqualError(t, err, "true: cannot be blank.")
+	type point struct{ X, Y int }
+	err = EachEntry[point](nil, []Rule[string]{Typed[string](Required)}).Validate(map[point]string{{1, 2}: ""})
+	assert.EqualError(t, err, "{1 2}: cannot be blank.")
+
+	// internal errors
+	err = EachEntry[string, int]([]Rule[string]{Typed[string](&validateInternalError{})}, nil).Validate(map[string]int{"internal": 1})
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+	err = EachEntry[int](nil, []Rule[string]{Typed[string](&validateInternalError{})}).Validate(map[int]string{1: "internal"})
+	_, ok = err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestEachSeq(t *testing.T) {
+	rule := EachSeq(Min(1))
+	assert.NoError(t, rule.Validate(nil))
+	assert.NoError(t, rule.Validate(slices.Values([]int{1, 2})))
+	assert.EqualError(t, rule.Validate(slices.Values([]int{1, -2, -1})), "1: must be no less than 1; 2: must be no less than 1.")
+
+	words := slices.Values(strings.Fields("abc xyz abc"))
+	err := EachSeq(Typed[string](&validateAbc{})).Validate(words)
+	assert.EqualError(t, err, "1: error abc.")
+
+	// stops at an internal error
+	n := 0
+	seq := func(yield func(string) bool) {
+		for _, s := range []string{"abc", "internal", "abc"} {
+			n++
+			if !yield(s) {
+				return
+			}
+		}
+	}
+	_, ok := EachSeq(Typed[string](&validateInternalError{})).Validate(seq).(InternalError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+}
+
+func TestEachSeq2(t *testing.T) {
+	rule := EachSeq2([]Rule[string]{Typed[string](Length(1, 3))}, []Rule[int]{Min(1)})
+	assert.NoError(t, rule.Validate(nil))
+	assert.NoError(t, rule.Validate(maps.All(map[string]int{"a": 1})))
+	assert.EqualError(t, rule.Validate(maps.All(map[string]int{"abcd": 1, "b": -1})), "abcd: key: the length must be between 1 and 3; b: must be no less than 1.")
+
+	// pairs with the same key
+	seq := func(yield func(string, int) bool) {
+		_ = yield("a", -2) && yield("a", -1)
+	}
+	err := rule.Validate(seq)
+	assert.EqualError(t, err, "a: must be no less than 1, must be no less than 1.")
+
+	err = EachSeq2[int](nil, []Rule[string]{Typed[string](Required)}).Validate(slices.All([]string{"a", ""}))
+	assert.EqualError(t, err, "1: cannot be blank.")
+}
+
+func TestJoinErrors(t *testing.T) {
+	e1, e2, e3 := errors.New("e1"), errors.New("e2"), errors.New("e3")
+	assert.Nil(t, joinErrors(nil, nil))
+	assert.Equal(t, e1, joinErrors(e1, nil))
+	assert.Equal(t, e2, joinErrors(nil, e2))
+	assert.EqualError(t, joinErrors(joinErrors(e1, e2), e3), "e1, e2, e3")
+}
@@ -0,0 +1,251 @@
+package kv
+
+import (
+	"context"
+	"regexp"
+)
+
+var (
+	// ErrMapTooFewKeys is the error returned when a map contains too few keys.
+	ErrMapTooFewKeys = NewError("validation_map_too_few_keys", "must contain at least {{.min}} keys")
+
+	// ErrMapTooManyKeys is the error returned when a map contains too many keys.
+	ErrMapTooManyKeys = NewError("validation_map_too_many_keys", "must contain no more than {{.max}} keys")
+
+	// ErrKeyDependent is the error returned when a key is missing while a key depending on it is present.
+	ErrKeyDependent = NewError("validation_key_dependent", "required key is missing when {{.key}} is present")
+)
+
+type (
+	// MapOfRule is a validation rule that checks the keys and values of a map of type map[K]V.
+	MapOfRule[K comparable, V any] struct {
+		keys           []mapKey[K, V]
+		patterns       []mapPattern[V]
+		dependencies   []mapDependency[K]
+		extraRules     []Rule[V]
+		hasExtraRules  bool
+		allowExtraKeys bool
+		minKeys        int
+		maxKeys        int
+	}
+
+	mapKey[K comparable, V any] struct {
+		key      K
+		optional bool
+		rules    []Rule[V]
+	}
+
+	mapPattern[V any] struct {
+		re    *regexp.Regexp
+		rules []Rule[V]
+	}
+
+	mapDependency[K comparable] struct {
+		key      K
+		required []K
+	}
+)
+
+// MapOf returns a validation rule that checks the keys and values of a map of type map[K]V.
+// Unlike Map, the values are validated by rules written for their type, and the keys can be
+// matched by patterns. The rule is configured by chaining its methods. For example,
+//
+//	kv.MapOf[string, any]().
+//	    Key("name", kv.Required).
+//	    OptionalKey("card", kv.Length(16, 19)).
+//	    Pattern(regexp.MustCompile(`^x-`), kv.Length(0, 64)).
+//	    Dependent("card", "cvv").
+//	    MaxKeys(20)
+//
+// Keys that are neither specified by Key nor matched by a pattern are reported with ErrKeyUnexpected,
+// unless Extra or AllowExtraKeys is called. A nil map is considered valid.
+// Use the Required rule to make sure a map value is present.
+func MapOf[K comparable, V any]() MapOfRule[K, V] {
+	return MapOfRule[K, V]{}
+}
+
+// Key specifies a key that must be present in the map and the rules validating its value.
+func (r MapOfRule[K, V]) Key(key K, rules ...Rule[V]) MapOfRule[K, V] {
+	r.keys = append(r.keys[:len(r.keys):len(r.keys)], mapKey[K, V]{key: key, rules: rules})
+	return r
+}
+
+// OptionalKey specifies a key that may be missing from the map and the rules validating its value.
+func (r MapOfRule[K, V]) OptionalKey(key K, rules ...Rule[V]) MapOfRule[K, V] {
+	r.keys = append(r.keys[:len(r.keys):len(r.keys)], mapKey[K, V]{key: key, optional: true, rules: rules})
+	return r
+}
+
+// Pattern specifies the rules validating the values of all keys matching the regular expression,
+// like the "patternProperties" keyword of JSON Schema. The keys are matched in their fmt representation.
+// A key matching several patterns, or specified by Key as well, is validated by all of their rules.
+func (r MapOfRule[K, V]) Pattern(re *regexp.Regexp, rules ...Rule[V]) MapOfRule[K, V] {
+	r.patterns = append(r.patterns[:len(r.patterns):len(r.patterns)], mapPattern[V]{re: re, rules: rules})
+	return r
+}
+
+// Extra specifies the rules validating the values of the keys that are neither specified by Key
+// nor matched by a pattern. Calling Extra allows such keys to be present in the map.
+func (r MapOfRule[K, V]) Extra(rules ...Rule[V]) MapOfRule[K, V] {
+	r.extraRules = rules
+	r.hasExtraRules = true
+	return r
+}
+
+// AllowExtraKeys configures the rule to ignore the keys that are neither specified by Key nor matched by a pattern.
+func (r MapOfRule[K, V]) AllowExtraKeys() MapOfRule[K, V] {
+	r.allowExtraKeys = true
+	return r
+}
+
+// MinKeys configures the rule to check if the map contains at least min keys.
+func (r MapOfRule[K, V]) MinKeys(min int) MapOfRule[K, V] {
+	r.minKeys = min
+	return r
+}
+
+// MaxKeys configures the rule to check if the map contains no more than max keys.
+func (r MapOfRule[K, V]) MaxKeys(max int) MapOfRule[K, V] {
+	r.maxKeys = max
+	return r
+}
+
+// Dependent specifies that the required keys must be present in the map when the key is present.
+// Each missing key is reported with ErrKeyDependent, whose "key" param is the name of the key depending on it.
+func (r MapOfRule[K, V]) Dependent(key K, required ...K) MapOfRule[K, V] {
+	r.dependencies = append(r.dependencies[:len(r.dependencies):len(r.dependencies)], mapDependency[K]{key: key, required: required})
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r MapOfRule[K, V]) Validate(m map[K]V) error {
+	return r.ValidateWithContext(context.TODO(), m)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+// The number of keys is checked first, and the keys are not validated if it is out of range.
+// If the context carries a field mask, only the present keys are validated, and neither the number of keys
+// nor the dependent keys are checked, because the map holds only part of the data.
+func (r MapOfRule[K, V]) ValidateWithContext(ctx context.Context, m map[K]V) error {
+	if m == nil {
+		// treat a nil map as valid
+		return nil
+	}
+	mask, track := FieldMaskFromContext(ctx), tracksPath(ctx)
+	partial := mask != nil
+	if r.minKeys > 0 && len(m) < r.minKeys && !partial {
+		return ErrMapTooFewKeys.SetParams(map[string]any{"min": r.minKeys})
+	}
+	if r.maxKeys > 0 && len(m) > r.maxKeys && !partial {
+		return ErrMapTooManyKeys.SetParams(map[string]any{"max": r.maxKeys})
+	}
+
+	errs := Errors{}
+	known := make(map[K]bool, len(r.keys))
+	for _, kr := range r.keys {
+		known[kr.key] = true
+		kctx, present := ctx, true
+		if mask != nil || track {
+			name := formatKey(kr.key)
+			if kctx, present = maskField(ctx, mask, name); !present {
+				// skip the keys absent from the field mask
+				continue
+			}
+			if track {
+				kctx = withPathElement(kctx, name)
+			}
+		}
+		v, ok := m[kr.key]
+		if !ok {
+			if !kr.optional {
+				errs[formatKey(kr.key)] = ErrKeyMissing
+			}
+			continue
+		}
+		if err := validateValueWithContext(kctx, v, kr.rules...); err != nil {
+			if isInternalError(err) {
+				return err
+			}
+			errs[formatKey(kr.key)] = err
+		}
+	}
+
+	for k, v := range m {
+		name := formatKey(k)
+		kctx, present := maskField(ctx, mask, name)
+		if !present {
+			continue
+		}
+		if track {
+			kctx = withPathElement(kctx, name)
+		}
+		matched := known[k]
+		for _, p := range r.patterns {
+			if !p.re.MatchString(name) {
+				continue
+			}
+			matched = true
+			if err := validateValueWithContext(kctx, v, p.rules...); err != nil {
+				if isInternalError(err) {
+					return err
+				}
+				errs[name] = joinErrors(errs[name], err)
+			}
+		}
+		if matched || r.allowExtraKeys && !r.hasExtraRules {
+			continue
+		}
+		if !r.hasExtraRules {
+			errs[name] = ErrKeyUnexpected
+		} else if err := validateValueWithContext(kctx, v, r.extraRules...); err != nil {
+			if isInternalError(err) {
+				return err
+			}
+			errs[name] = err
+		}
+	}
+
+	for _, d := range r.dependencies {
+		if _, ok := m[d.key]; !ok || partial {
+			continue
+		}
+		for _, k := range d.required {
+			if _, ok := m[k]; ok {
+				continue
+			}
+			if name := formatKey(k); errs[name] == nil {
+				errs[name] = ErrKeyDependent.SetParams(map[string]any{"key": formatKey(d.key)})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (r MapOfRule[K, V]) ruleLists() []namedRules {
+	lists := make([]namedRules, 0, len(r.keys)+len(r.patterns)+1)
+	for _, k := range r.keys {
+		lists = append(lists, namedRules{name: formatKey(k.key), rules: anyRules(k.rules)})
+	}
+	for _, p := range r.patterns {
+		lists = append(lists, namedRules{rules: anyRules(p.rules)})
+	}
+	return append(lists, namedRules{rules: anyRules(r.extraRules)})
+}
+
+func (r MapOfRule[K, V]) referencesFields() bool {
+	for _, k := range r.keys {
+		if rulesReferenceFields(k.rules) {
+			return true
+		}
+	}
+	for _, p := range r.patterns {
+		if rulesReferenceFields(p.rules) {
+			return true
+		}
+	}
+	return rulesReferenceFields(r.extraRules)
+}
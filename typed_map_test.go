@@ -0,0 +1,85 @@
+package kv
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+func TestMapOf(t *testing.T) {
+	ext := regexp.MustCompile(`^x-`)
+	tests := []struct {
+		tag   string
+		rule  MapOfRule[string, any]
+		value map[string]any
+		err   string
+	}{
+		// exact keys
+		{"t1.1", MapOf[string, any](), nil, ""},
+		{"t1.2", MapOf[string, any]().Key("a", Required), map[string]any{"a": "x"}, ""},
+		{"t1.3", MapOf[string, any]().Key("a", Required), map[string]any{"a": ""}, "a: cannot be blank."},
+		{"t1.4", MapOf[string, any]().Key("a"), map[string]any{}, "a: required key is missing."},
+		{"t1.5", MapOf[string, any]().OptionalKey("a", Required), map[string]any{}, ""},
+		{"t1.6", MapOf[string, any]().Key("a"), map[string]any{"a": 1, "b": 2}, "b: key not expected."},
+		{"t1.7", MapOf[string, any]().Key("a").AllowExtraKeys(), map[string]any{"a": 1, "b": 2}, ""},
+		// patterns
+		{"t2.1", MapOf[string, any]().Pattern(ext, Length(0, 3)), map[string]any{"x-a": "abc", "x-b": "ab"}, ""},
+		{"t2.2", MapOf[string, any]().Pattern(ext, Length(0, 3)), map[string]any{"x-a": "abcd"}, "x-a: the length must be no more than 3."},
+		{"t2.3", MapOf[string, any]().Pattern(ext, Length(0, 3)), map[string]any{"y": "abcd"}, "y: key not expected."},
+		{"t2.4", MapOf[string, any]().Key("x-a", Required).Pattern(ext, Length(0, 3)), map[string]any{"x-a": "abcd"}, "x-a: the length must be no more than 3."},
+		{"t2.5", MapOf[string, any]().Pattern(ext, Length(0, 3)).Pattern(regexp.MustCompile(`b$`), In("ab")), map[string]any{"x-b": "abcd"}, "x-b: the length must be no more than 3, must be a valid value."},
+		// extra keys
+		{"t3.1", MapOf[string, any]().Key("a").Extra(Length(0, 2)), map[string]any{"a": "abc", "b": "ab"}, ""},
+		{"t3.2", MapOf[string, any]().Key("a").Extra(Length(0, 2)), map[string]any{"a": "abc", "b": "abc"}, "b: the length must be no more than 2."},
+		{"t3.3", MapOf[string, any]().Pattern(ext).Extra(Length(0, 2)).AllowExtraKeys(), map[string]any{"x-a": "abc", "b": "abc"}, "b: the length must be no more than 2."},
+		// key counts
+		{"t4.1", MapOf[string, any]().AllowExtraKeys().MinKeys(2), map[string]any{"a": 1}, "must contain at least 2 keys"},
+		{"t4.2", MapOf[string, any]().AllowExtraKeys().MinKeys(2), map[string]any{"a": 1, "b": 2}, ""},
+		{"t4.3", MapOf[string, any]().AllowExtraKeys().MaxKeys(1), map[string]any{"a": 1, "b": 2}, "must contain no more than 1 keys"},
+		{"t4.4", MapOf[string, any]().MinKeys(2), nil, ""},
+		// dependent keys
+		{"t5.1", MapOf[string, any]().AllowExtraKeys().Dependent("card", "cvv", "expiry"), map[string]any{"name": "a"}, ""},
+		{"t5.2", MapOf[string, any]().AllowExtraKeys().Dependent("card", "cvv", "expiry"), map[string]any{"card": "a", "cvv": "123", "expiry": "01/30"}, ""},
+		{"t5.3", MapOf[string, any]().AllowExtraKeys().Dependent("card", "cvv", "expiry"), map[string]any{"card": "a", "cvv": "123"}, "expiry: required key is missing when card is present."},
+		{"t5.4", MapOf[string, any]().Key("cvv").Dependent("card", "cvv"), map[string]any{"card": "a"}, "card: key not expected; cvv: required key is missing."},
+	}
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		assertError(t, test.err, ValidateValue(test.value, test.rule), test.tag)
+	}
+}
+
+func TestMapOfTyped(t *testing.T) {
+	rule := MapOf[int, string]().Key(1, Typed[string](Required)).Pattern(regexp.MustCompile(`^[0-9]$`), Typed[string](Length(0, 2)))
+	assert.NoError(t, rule.Validate(map[int]string{1: "a", 2: "bc"}))
+	assert.EqualError(t, rule.Validate(map[int]string{1: "", 2: "bcd", 10: "a"}), "1: cannot be blank; 10: key not expected; 2: the length must be no more than 2.")
+}
+
+func TestMapOfWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	rule := MapOf[string, any]().Key("a", WithContext(func(ctx context.Context, value any) error {
+		if value != ctx.Value(contains) {
+			return NewError("code", "unexpected value")
+		}
+		return nil
+	}))
+	assert.NoError(t, rule.ValidateWithContext(ctx, map[string]any{"a": "abc"}))
+	assert.EqualError(t, rule.ValidateWithContext(ctx, map[string]any{"a": "xyz"}), "a: unexpected value.")
+
+	// internal errors
+	err := MapOf[string, any]().Extra(&validateInternalError{}).Validate(map[string]any{"a": "internal"})
+	_, ok := err.(InternalError)
+	assert.True(t, ok)
+}
+
+func TestMapOfInStruct(t *testing.T) {
+	type config struct {
+		Labels map[string]any
+	}
+	c := config{Labels: map[string]any{"app": "web", "x-team": ""}}
+	err := ValidateStruct(&c, Field(&c.Labels, Any(MapOf[string, any]().Key("app", Required).Pattern(regexp.MustCompile(`^x-`), Required))))
+	assert.EqualError(t, err, "Labels: (x-team: cannot be blank.).")
+}
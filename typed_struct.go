@@ -0,0 +1,146 @@
+package kv
+
+import (
+	"context"
+)
+
+type (
+	// StructRules represents a rule set for validating structs of type T without reflection.
+	// A StructRules is meant to be built once, for example as a package variable, and reused.
+	// It is safe for concurrent use.
+	StructRules[T any] struct {
+		fields []*TypedFieldRules[T]
+	}
+
+	// TypedFieldRules represents a rule set associated with a struct field that is accessed through a function.
+	TypedFieldRules[T any] struct {
+		name     string
+		embedded bool
+		validate func(ctx context.Context, structPtr *T) error
+		// rules are the rules of the field, checked by Compile.
+		rules []any
+	}
+)
+
+// Struct returns a rule set that validates structs of type T by checking the specified fields against
+// the corresponding validation rules. Use FieldOf() to specify the struct fields that need to be validated.
+// For example,
+//
+//	var customerRules = kv.Struct[Customer](
+//	    kv.FieldOf("name", func(c *Customer) *string { return &c.Name }, kv.Typed[string](kv.Required)),
+//	    kv.FieldOf("age", func(c *Customer) *int { return &c.Age }, kv.Min(18)),
+//	)
+//
+//	err := customerRules.Validate(&c)
+//
+// Unlike ValidateStruct, the fields are accessed through the given functions, so no reflection is needed
+// to find them. The errors are reported in the same Errors shape, keyed by the given field names.
+func Struct[T any](fields ...*TypedFieldRules[T]) StructRules[T] {
+	return StructRules[T]{fields: fields}
+}
+
+// Field returns a copy of the rule set with the given fields added.
+func (r StructRules[T]) Field(fields ...*TypedFieldRules[T]) StructRules[T] {
+	r.fields = append(r.fields[:len(r.fields):len(r.fields)], fields...)
+	return r
+}
+
+// Validate validates the struct that the given pointer points to.
+// If the pointer is nil, it is considered valid.
+func (r StructRules[T]) Validate(structPtr *T) error {
+	return r.ValidateWithContext(context.TODO(), structPtr)
+}
+
+// ValidateWithContext validates the struct that the given pointer points to with the given context.
+// If the pointer is nil, it is considered valid.
+func (r StructRules[T]) ValidateWithContext(ctx context.Context, structPtr *T) error {
+	return Translate(ctx, r.validateRaw(ctx, structPtr))
+}
+
+func (r StructRules[T]) validateRaw(ctx context.Context, structPtr *T) error {
+	if structPtr == nil {
+		// treat a nil struct pointer as valid
+		return nil
+	}
+
+	var errs Errors
+	mask, track := FieldMaskFromContext(ctx), tracksPath(ctx)
+	for _, fr := range r.fields {
+		fctx := ctx
+		if !fr.embedded {
+			var present bool
+			if fctx, present = maskField(ctx, mask, fr.name); !present {
+				// skip the fields absent from the field mask
+				continue
+			}
+			if track {
+				fctx = withPathElement(fctx, fr.name)
+			}
+		}
+		err := fr.validate(fctx, structPtr)
+		if err == nil {
+			continue
+		}
+		if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+		if errs == nil {
+			errs = Errors{}
+		}
+		if fr.embedded {
+			// merge errors from embedded struct field
+			if es, ok := err.(Errors); ok {
+				for name, value := range es {
+					errs[name] = value
+				}
+				continue
+			}
+		}
+		errs[fr.name] = err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// FieldOf specifies a struct field and the corresponding validation rules.
+// The name is used as the key of the field in the returned Errors, and get must return a pointer
+// to the field of the given struct. If the field type implements Validatable or ValidatableWithContext,
+// its validation method is called after all rules pass. Transform rules adapted by Typed modify the field
+// through the pointer returned by get.
+func FieldOf[T, F any](name string, get func(*T) *F, rules ...Rule[F]) *TypedFieldRules[T] {
+	transform := hasTransformer(rules)
+	return &TypedFieldRules[T]{
+		name:  name,
+		rules: anyRules(rules),
+		validate: func(ctx context.Context, structPtr *T) error {
+			if transform {
+				return validateTransformedValue(ctx, get(structPtr), rules)
+			}
+			if ctx == nil {
+				return ValidateValue(*get(structPtr), rules...)
+			}
+			return validateValueWithContext(ctx, *get(structPtr), rules...)
+		},
+	}
+}
+
+// Embedded marks the field as an embedded struct, so that the Errors returned by its
+// validation are merged into the Errors of the enclosing struct.
+func (r *TypedFieldRules[T]) Embedded() *TypedFieldRules[T] {
+	r.embedded = true
+	return r
+}
+
+func (r StructRules[T]) ruleLists() []namedRules {
+	lists := make([]namedRules, len(r.fields))
+	for i, fr := range r.fields {
+		lists[i].rules = fr.rules
+		if !fr.embedded {
+			lists[i].name = fr.name
+		}
+	}
+	return lists
+}
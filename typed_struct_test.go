@@ -0,0 +1,82 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type typedModel struct {
+	Model3
+	Name  string
+	Age   int
+	Inner Model3
+	Str   String123
+}
+
+var typedModelRules = Struct[typedModel](
+	FieldOf("name", func(m *typedModel) *string { return &m.Name }, Typed[string](Required), Typed[string](Length(2, 5))),
+	FieldOf("age", func(m *typedModel) *int { return &m.Age }, Min(18), Max(130)),
+)
+
+func TestStruct(t *testing.T) {
+	var m0 *typedModel
+	tests := []struct {
+		tag   string
+		model *typedModel
+		rules StructRules[typedModel]
+		err   string
+	}{
+		{"t1.1", m0, typedModelRules, ""},
+		{"t1.2", &typedModel{Name: "abc", Age: 20}, typedModelRules, ""},
+		{"t1.3", &typedModel{Name: "abcdef", Age: 200}, typedModelRules, "age: must be no greater than 130; name: the length must be between 2 and 5."},
+		{"t1.4", &typedModel{Age: 20}, typedModelRules, "name: cannot be blank."},
+		// skip
+		{"t2.1", &typedModel{}, Struct(FieldOf("name", func(m *typedModel) *string { return &m.Name }, Typed[string](Skip), Typed[string](Required))), ""},
+		// validatable fields
+		{"t3.1", &typedModel{Str: "abc"}, Struct(FieldOf("str", func(m *typedModel) *String123 { return &m.Str })), "str: error 123."},
+		{"t3.2", &typedModel{Str: "123"}, Struct(FieldOf("str", func(m *typedModel) *String123 { return &m.Str })), ""},
+		{"t3.3", &typedModel{}, Struct(FieldOf("inner", func(m *typedModel) *Model3 { return &m.Inner })), "inner: (A: error abc.)."},
+		// embedded structs
+		{"t4.1", &typedModel{}, Struct(FieldOf("Model3", func(m *typedModel) *Model3 { return &m.Model3 }).Embedded()), "A: error abc."},
+		{"t4.2", &typedModel{Model3: Model3{A: "abc"}}, Struct(FieldOf("Model3", func(m *typedModel) *Model3 { return &m.Model3 }).Embedded()), ""},
+		// internal error
+		{"t5.1", &typedModel{Name: "internal"}, typedModelRules.Field(FieldOf("name", func(m *typedModel) *string { return &m.Name }, Typed[string](&validateInternalError{}))), "error internal"},
+	}
+	for _, test := range tests {
+		err1 := test.rules.Validate(test.model)
+		err2 := test.rules.ValidateWithContext(context.Background(), test.model)
+		assertError(t, test.err, err1, test.tag)
+		assertError(t, test.err, err2, test.tag)
+	}
+
+	// the rule set can be used wherever a Rule[*T] is accepted
+	assert.EqualError(t, ValidateValue(&typedModel{Age: 20}, typedModelRules), "name: cannot be blank.")
+}
+
+func TestStructRules_Field(t *testing.T) {
+	base := Struct(FieldOf("name", func(m *typedModel) *string { return &m.Name }, Typed[string](Required)))
+	extended := base.Field(FieldOf("age", func(m *typedModel) *int { return &m.Age }, Min(18)))
+
+	m := typedModel{Name: "abc", Age: 1}
+	assert.NoError(t, base.Validate(&m))
+	assert.EqualError(t, extended.Validate(&m), "age: must be no less than 18.")
+}
+
+func TestStructWithContext(t *testing.T) {
+	rules := Struct(FieldOf("name", func(m *typedModel) *string { return &m.Name }, Typed[string](&validateContextAbc{})))
+	ctx := context.Background()
+	assert.NoError(t, rules.ValidateWithContext(ctx, &typedModel{Name: "abc"}))
+	assert.EqualError(t, rules.ValidateWithContext(ctx, &typedModel{Name: "xyz"}), "name: error abc.")
+}
+
+func TestStructAllocs(t *testing.T) {
+	rules := Struct(FieldOf("age", func(m *typedModel) *int { return &m.Age }, Min(18), Max(130)))
+	m := typedModel{Age: 500}
+	allocs := testing.AllocsPerRun(100, func() {
+		m.Age = 20
+		_ = rules.Validate(&m)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
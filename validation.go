@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 type (
@@ -37,6 +38,13 @@ type (
 		ValidateWithContext(ctx context.Context, value any) error
 	}
 
+	// TypedRuleWithContext represents a context-aware validation rule for values of type T.
+	// RuleWithContext is equivalent to TypedRuleWithContext[any].
+	TypedRuleWithContext[T any] interface {
+		// ValidateWithContext validates a value and returns a value if validation fails.
+		ValidateWithContext(ctx context.Context, value T) error
+	}
+
 	// RuleFunc represents a validator function.
 	// You may wrap it as a Rule by calling By().
 	RuleFunc func(value any) error
@@ -51,7 +59,7 @@ var (
 	ErrorTag = "json"
 
 	// Skip is a special validation rule that indicates all rules following it should be skipped.
-	Skip = skipRule{skip: true}
+	Skip = skipRule{skip: fixedCondition(true)}
 
 	validatableType            = reflect.TypeOf((*Validatable)(nil)).Elem()
 	validatableWithContextType = reflect.TypeOf((*ValidatableWithContext)(nil)).Elem()
@@ -67,7 +75,7 @@ var (
 //     for each element call the element value's `Validate()`. Return with the validation result.
 func Validate(value any, rules ...Rule[any]) error {
 	for _, rule := range rules {
-		if s, ok := rule.(skipRule); ok && s.skip {
+		if s, ok := rule.(skipper[any]); ok && s.skipped(context.TODO(), value) {
 			return nil
 		}
 		if err := rule.Validate(value); err != nil {
@@ -113,16 +121,31 @@ func Validate(value any, rules ...Rule[any]) error {
 //     for each element call the element value's `ValidateWithContext()`. Return with the validation result.
 //  5. If the value being validated is a map/slice/array, and the element type implements `Validatable`,
 //     for each element call the element value's `Validate()`. Return with the validation result.
+//
+// If the context carries a locale set by WithLocale, the validation errors are translated into the locale.
 func ValidateWithContext(ctx context.Context, value any, rules ...Rule[any]) error {
-	for _, rule := range rules {
-		if s, ok := rule.(skipRule); ok && s.skip {
+	return Translate(ctx, validateWithContext(ctx, value, rules...))
+}
+
+// validateWithContext validates the given value with the given context without translating the validation errors.
+func validateWithContext(ctx context.Context, value any, rules ...Rule[any]) error {
+	trace := traceFromContext(ctx)
+	for i, rule := range rules {
+		if s, ok := rule.(skipper[any]); ok && s.skipped(ctx, value) {
+			if trace != nil {
+				traceSkipped(ctx, trace, rules[i+1:])
+			}
 			return nil
 		}
-		if rc, ok := rule.(RuleWithContext); ok {
-			if err := rc.ValidateWithContext(ctx, value); err != nil {
-				return err
-			}
-		} else if err := rule.Validate(value); err != nil {
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		err := validateRule(ctx, rule, value)
+		if trace != nil {
+			trace.record(ctx, rule, value, start, err)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -156,12 +179,188 @@ func ValidateWithContext(ctx context.Context, value any, rules ...Rule[any]) err
 			return validateSlice(rv)
 		}
 	case reflect.Ptr, reflect.Interface:
-		return ValidateWithContext(ctx, rv.Elem().Interface())
+		return validateWithContext(ctx, rv.Elem().Interface())
+	}
+
+	return nil
+}
+
+// ValidateAll validates the given value like Validate, except that it does not stop at the first failing rule.
+// Every rule is run and the errors of the failing rules are returned as an ErrorList. For example,
+//
+//	err := kv.ValidateAll("a-", kv.Length(5, 10), is.Alphanumeric)
+//	fmt.Println(err)
+//	// the length must be between 5 and 10, must contain English letters and digits only
+//
+// A Skip rule still stops the validation, and an InternalError returned by a rule is returned immediately.
+// If the value implements `Validatable` (or is a map/slice/array of such values), it is only validated
+// by its `Validate()` when all rules pass.
+func ValidateAll(value any, rules ...Rule[any]) error {
+	if skipped, err := validateAll(nil, value, rules); skipped || err != nil {
+		return err
+	}
+	return Validate(value)
+}
+
+// ValidateAllWithContext validates the given value with the given context like ValidateWithContext,
+// except that it runs every rule and returns the errors of the failing rules as an ErrorList.
+// Please refer to ValidateAll for the details.
+func ValidateAllWithContext(ctx context.Context, value any, rules ...Rule[any]) error {
+	return Translate(ctx, validateAllWithContext(ctx, value, rules))
+}
+
+// validateAllWithContext validates the given value like ValidateAllWithContext without translating the validation errors.
+func validateAllWithContext(ctx context.Context, value any, rules []Rule[any]) error {
+	if skipped, err := validateAll(ctx, value, rules); skipped || err != nil {
+		return err
+	}
+	return validateWithContext(ctx, value)
+}
+
+// validateAll runs every rule against the value and collects the errors into an ErrorList.
+// It reports whether the validation was stopped by a Skip rule.
+func validateAll(ctx context.Context, value any, rules []Rule[any]) (bool, error) {
+	var errs ErrorList
+	trace := traceFromContext(ctx)
+	for i, rule := range rules {
+		if s, ok := rule.(skipper[any]); ok && s.skipped(ctx, value) {
+			if trace != nil {
+				traceSkipped(ctx, trace, rules[i+1:])
+			}
+			return true, errs.Filter()
+		}
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		err := validateRule(ctx, rule, value)
+		if trace != nil {
+			trace.record(ctx, rule, value, start, err)
+		}
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return false, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return false, errs.Filter()
+}
+
+// ValidateValue validates the given value of type T against rules written for T and returns the validation error, if any.
+//
+// Unlike Validate, ValidateValue neither boxes the value into an interface nor uses reflection to run the rules,
+// so generic rules such as Min and Max can be applied directly. For example,
+//
+//	err := kv.ValidateValue(age, kv.Min(18), kv.Max(130))
+//
+// ValidateValue performs validation using the following steps:
+//  1. For each rule, call its `Validate()` to validate the value. Return if any error is found.
+//     A rule returned by SkipOf stops the validation.
+//  2. If the value being validated implements `Validatable`, call the value's `Validate()`.
+//     Return with the validation result.
+func ValidateValue[T any](value T, rules ...Rule[T]) error {
+	for _, rule := range rules {
+		if s, ok := rule.(skipper[T]); ok && s.skipped(context.TODO(), value) {
+			return nil
+		}
+		if err := rule.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := typedValidatable[T, Validatable](value); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// ValidateValueWithContext validates the given value of type T with the given context and returns the validation error, if any.
+//
+// ValidateValueWithContext performs validation using the following steps:
+//  1. For each rule, call its `ValidateWithContext()` to validate the value if the rule implements `TypedRuleWithContext[T]`.
+//     Otherwise call `Validate()` of the rule. Return if any error is found.
+//  2. If the value being validated implements `ValidatableWithContext`, call the value's `ValidateWithContext()`
+//     and return with the validation result.
+//  3. If the value being validated implements `Validatable`, call the value's `Validate()`
+//     and return with the validation result.
+//
+// If the context carries a locale set by WithLocale, the validation errors are translated into the locale.
+func ValidateValueWithContext[T any](ctx context.Context, value T, rules ...Rule[T]) error {
+	return Translate(ctx, validateValueWithContext(ctx, value, rules...))
+}
+
+// validateValueWithContext validates the given value with the given context without translating the validation errors.
+func validateValueWithContext[T any](ctx context.Context, value T, rules ...Rule[T]) error {
+	trace := traceFromContext(ctx)
+	for i, rule := range rules {
+		if s, ok := rule.(skipper[T]); ok && s.skipped(ctx, value) {
+			if trace != nil {
+				traceSkipped(ctx, trace, rules[i+1:])
+			}
+			return nil
+		}
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		err := validateRule(ctx, rule, value)
+		if trace != nil {
+			trace.record(ctx, rule, value, start, err)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
+	if v, ok := typedValidatable[T, ValidatableWithContext](value); ok {
+		return v.ValidateWithContext(ctx)
+	}
+	if v, ok := typedValidatable[T, Validatable](value); ok {
+		return v.Validate()
+	}
 	return nil
 }
 
+// validateRule validates a value using a single rule, passing the context along if the rule is context-aware.
+// The errors are not translated.
+func validateRule[T any](ctx context.Context, rule Rule[T], value T) error {
+	if ctx == nil {
+		return rule.Validate(value)
+	}
+	if rr, ok := rule.(rawRule[T]); ok {
+		return rr.validateRaw(ctx, value)
+	}
+	if rc, ok := rule.(TypedRuleWithContext[T]); ok {
+		return rc.ValidateWithContext(ctx, value)
+	}
+	return rule.Validate(value)
+}
+
+// typedValidatable returns the value as I if it implements I and is not a nil pointer.
+// The value is only boxed into an interface when its type implements I, so that
+// validating plain values like numbers and strings does not allocate.
+func typedValidatable[T, I any](value T) (I, bool) {
+	var (
+		zero T
+		none I
+	)
+	if z := any(zero); z != nil {
+		// T is not an interface type, so its method set decides alone.
+		if _, ok := z.(I); !ok {
+			return none, false
+		}
+	}
+	v, ok := any(value).(I)
+	if !ok {
+		return none, false
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return none, false
+	}
+	return v, true
+}
+
 // validateMap validates a map of validatable elements
 func validateMap(rv reflect.Value) error {
 	errs := Errors{}
@@ -228,16 +427,86 @@ func validateSliceWithContext(ctx context.Context, rv reflect.Value) error {
 	return nil
 }
 
+// skipper is implemented by rules for values of type T that may stop the validation of the rules following them.
+// It takes the value as T, so that the typed validations do not box the value into an interface.
+type skipper[T any] interface {
+	skipped(ctx context.Context, value T) bool
+}
+
+// rawRule is implemented by the rules that translate their errors when they are called directly, such as StructRules.
+// When nested in another validation, they are run without translating, so that the errors are translated once.
+type rawRule[T any] interface {
+	validateRaw(ctx context.Context, value T) error
+}
+
 type skipRule struct {
+	skip condition
+}
+
+func (r skipRule) Validate(value any) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext returns the error of the condition, if any, as the rule is only run when it does not skip.
+func (r skipRule) ValidateWithContext(ctx context.Context, value any) error {
+	_, err := r.skip.met(ctx, value)
+	return err
+}
+
+func (r skipRule) skipped(ctx context.Context, value any) bool {
+	met, err := r.skip.met(ctx, value)
+	return err == nil && met
+}
+
+func (r skipRule) referencesFields() bool {
+	return r.skip.field
+}
+
+// When determines if all rules following it should be skipped.
+func (r skipRule) When(condition bool) skipRule {
+	r.skip = fixedCondition(condition)
+	return r
+}
+
+// WhenFunc sets the function that determines if all rules following it should be skipped.
+// The function is called each time a value is validated, with the context of the validation.
+func (r skipRule) WhenFunc(f func(ctx context.Context) bool) skipRule {
+	r.skip = funcCondition(f)
+	return r
+}
+
+// WhenValue sets the function that determines if all rules following it should be skipped for the value being validated.
+func (r skipRule) WhenValue(f func(value any) bool) skipRule {
+	r.skip = valueCondition(f)
+	return r
+}
+
+// WhenField sets the function that determines if all rules following it should be skipped for the value of
+// another field. Please refer to the WhenField function for the details.
+func (r skipRule) WhenField(fieldPtr any, f func(value any) bool) skipRule {
+	r.skip = fieldCondition(fieldPtr, f)
+	return r
+}
+
+// SkipOf returns the Skip rule for values of type T, to be used with ValidateValue.
+func SkipOf[T any]() typedSkipRule[T] {
+	return typedSkipRule[T]{skip: true}
+}
+
+type typedSkipRule[T any] struct {
 	skip bool
 }
 
-func (r skipRule) Validate(any) error {
+func (r typedSkipRule[T]) Validate(T) error {
 	return nil
 }
 
+func (r typedSkipRule[T]) skipped(context.Context, T) bool {
+	return r.skip
+}
+
 // When determines if all rules following it should be skipped.
-func (r skipRule) When(condition bool) skipRule {
+func (r typedSkipRule[T]) When(condition bool) typedSkipRule[T] {
 	r.skip = condition
 	return r
 }
@@ -78,6 +78,128 @@ func TestValidate(t *testing.T) {
 	assert.EqualError(t, err, "error xyz")
 }
 
+func TestValidateAll(t *testing.T) {
+	err := ValidateAll("123", &validateAbc{}, &validateXyz{})
+	assert.EqualError(t, err, "error abc, error xyz")
+	_, ok := err.(ErrorList)
+	assert.True(t, ok)
+	err = ValidateAll("abc", &validateAbc{}, &validateXyz{})
+	assert.EqualError(t, err, "error xyz")
+	_, ok = err.(ErrorList)
+	assert.True(t, ok)
+	assert.NoError(t, ValidateAll("abcxyz", &validateAbc{}, &validateXyz{}))
+
+	// skip and internal errors
+	assert.EqualError(t, ValidateAll("123", &validateAbc{}, Skip, &validateXyz{}), "error abc")
+	assert.NoError(t, ValidateAll("abc", &validateAbc{}, Skip, &validateXyz{}))
+	assert.EqualError(t, ValidateAll("123", &validateAbc{}, Skip.When(false), &validateXyz{}), "error abc, error xyz")
+	err = ValidateAll("internal", &validateAbc{}, &validateInternalError{}, &validateXyz{})
+	_, ok = err.(InternalError)
+	assert.True(t, ok)
+
+	// validatable values are validated when all rules pass
+	assert.EqualError(t, ValidateAll(String123("abc")), "error 123")
+	assert.EqualError(t, ValidateAll(String123("abc"), Length(5, 10)), "the length must be between 5 and 10")
+	assert.NoError(t, ValidateAll(String123("abc"), Skip))
+	assert.NoError(t, ValidateAll(nil, Length(5, 10)))
+}
+
+func TestValidateAllWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	err := ValidateAllWithContext(ctx, "xyz", WithContext(func(ctx context.Context, value any) error {
+		if value.(string) != ctx.Value(contains) {
+			return errors.New("unexpected value")
+		}
+		return nil
+	}), Length(5, 10), &validateContextXyz{})
+	assert.EqualError(t, err, "unexpected value, the length must be between 5 and 10")
+
+	assert.EqualError(t, ValidateAllWithContext(ctx, StringValidateContext("xyz")), "must be abc with context")
+	assert.EqualError(t, ValidateAllWithContext(ctx, StringValidateContext("xyz"), Length(5, 10)), "the length must be between 5 and 10")
+	assert.NoError(t, ValidateAllWithContext(ctx, "xyz", Skip, Length(5, 10)))
+
+	// translation
+	ctx = WithLocale(ctx, "fr")
+	err = ValidateAllWithContext(ctx, "a", Length(5, 10), In("b"))
+	assert.EqualError(t, err, "la longueur doit être comprise entre 5 et 10, doit être une valeur valide")
+}
+
+func TestValidateValue(t *testing.T) {
+	err := ValidateValue(5, Min(1), Max(10))
+	assert.NoError(t, err)
+	err = ValidateValue(20, Min(1), Max(10))
+	assert.EqualError(t, err, "must be no greater than 10")
+	err = ValidateValue(-1, Min(1), SkipOf[int](), Max(10))
+	assert.EqualError(t, err, "must be no less than 1")
+	err = ValidateValue(20, Min(1), SkipOf[int](), Max(10))
+	assert.NoError(t, err)
+	err = ValidateValue(20, Min(1), SkipOf[int]().When(false), Max(10))
+	assert.EqualError(t, err, "must be no greater than 10")
+
+	// validatable values
+	err = ValidateValue(String123("abc"))
+	assert.EqualError(t, err, "error 123")
+	err = ValidateValue(StringValidateContext("xyz"))
+	assert.EqualError(t, err, "must be abc")
+	var ptr *Model3
+	assert.NoError(t, ValidateValue(ptr))
+	var v Validatable = String123("abc")
+	assert.EqualError(t, ValidateValue(v), "error 123")
+
+	// rules for pointers
+	s := "abc"
+	assert.EqualError(t, ValidateValue(&s, EmptyRule[string]{}), "must be blank")
+	assert.NoError(t, ValidateValue[*string](nil, EmptyRule[string]{}))
+}
+
+func TestValidateValueWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	rule := typedContainsRule{}
+
+	assert.NoError(t, ValidateValueWithContext(ctx, "abc", rule))
+	assert.EqualError(t, ValidateValueWithContext(ctx, "xyz", rule), "unexpected value")
+	assert.NoError(t, ValidateValueWithContext(ctx, "xyz", SkipOf[string](), rule))
+	// the context-free path is used by ValidateValue
+	assert.EqualError(t, ValidateValue("abc", rule), "no context")
+
+	assert.EqualError(t, ValidateValueWithContext(ctx, StringValidateContext("xyz")), "must be abc with context")
+	assert.EqualError(t, ValidateValueWithContext(ctx, String123("abc")), "error 123")
+}
+
+func TestValidateValueAllocs(t *testing.T) {
+	rules := []Rule[int]{Min(1), Max(1000)}
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = ValidateValue(500, rules...)
+		_ = ValidateValue(5000, rules...)
+	})
+	assert.Equal(t, float64(0), allocs)
+
+	// a skip rule does not box the value
+	type big struct{ a, b, c, d [8]int }
+	skip := []Rule[big]{SkipOf[big](), Typed[big](Required)}
+	v := MustCompile(skip...)
+	allocs = testing.AllocsPerRun(100, func() {
+		_ = ValidateValue(big{}, skip...)
+		_ = validateValueWithContext(context.Background(), big{}, skip...)
+		_ = AllOf(skip...).Validate(big{})
+		_ = v.Validate(big{})
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+type typedContainsRule struct{}
+
+func (typedContainsRule) Validate(string) error {
+	return errors.New("no context")
+}
+
+func (typedContainsRule) ValidateWithContext(ctx context.Context, value string) error {
+	if !strings.Contains(value, ctx.Value(contains).(string)) {
+		return errors.New("unexpected value")
+	}
+	return nil
+}
+
 func stringEqual(str string) RuleFunc {
 	return func(value any) error {
 		s, _ := value.(string)
@@ -133,6 +255,30 @@ func Test_skipRule_Validate(t *testing.T) {
 	assert.Nil(t, Skip.Validate(100))
 }
 
+func Test_skipRule_WhenFunc(t *testing.T) {
+	draft := context.WithValue(context.Background(), roleKey{}, "draft")
+	skip := Skip.WhenFunc(func(ctx context.Context) bool { return ctx.Value(roleKey{}) == "draft" })
+	assertError(t, "", ValidateWithContext(draft, "", skip, Required), "t1")
+	assertError(t, "cannot be blank", ValidateWithContext(context.Background(), "", skip, Required), "t2")
+	assertError(t, "cannot be blank", Validate("", skip, Required), "t3")
+
+	skip = Skip.WhenValue(func(value any) bool { return value == "n/a" })
+	assertError(t, "", Validate("n/a", skip, Length(5, 10)), "t4")
+	assertError(t, "the length must be between 5 and 10", Validate("abc", skip, Length(5, 10)), "t5")
+
+	type item struct {
+		Kind string `json:"kind"`
+		Code string `json:"code"`
+	}
+	i := item{Kind: "virtual"}
+	virtual := func(value any) bool { return value == "virtual" }
+	assertError(t, "", ValidateStruct(&i, Field(&i.Code, Skip.WhenField(&i.Kind, virtual), Required)), "t6")
+	i.Kind = "physical"
+	assertError(t, "code: cannot be blank.", ValidateStruct(&i, Field(&i.Code, Skip.WhenField(&i.Kind, virtual), Required)), "t7")
+	// a field that cannot be found does not skip, and its error is returned
+	assertError(t, ErrFieldReference.Error(), Validate("", Skip.WhenField(&i.Kind, virtual), Required), "t8")
+}
+
 func assertError(t *testing.T, expected string, err error, tag string) {
 	if expected == "" {
 		assert.NoError(t, err, tag)
@@ -0,0 +1,101 @@
+package kv
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+type (
+	// WarnRule is a validation rule that reports the errors of another rule as warnings instead of failing.
+	WarnRule[T any] struct {
+		rule Rule[T]
+	}
+
+	// Warnings collects the warnings reported by Warn rules during a validation started by CollectWarnings.
+	// It is safe for concurrent use.
+	Warnings struct {
+		mu       sync.Mutex
+		warnings []FlatError
+	}
+
+	warningsKey struct{}
+)
+
+// Warn returns a validation rule that validates a value with the given rule, and reports the error of the rule
+// as a warning instead of returning it, so that the validation carries on with the rules following it.
+// The rule can be either a legacy rule or a typed rule. For example,
+//
+//	kv.Field(&u.Password, kv.Required, kv.Warn(kv.Length(12, 0).Error("is weak")), kv.Length(8, 0))
+//
+// The warnings are collected by validating with the context returned by CollectWarnings, and are dropped otherwise.
+// An InternalError returned by the rule is still returned.
+func Warn[T any](rule Rule[T]) WarnRule[T] {
+	return WarnRule[T]{rule: rule}
+}
+
+// Validate checks the given value, and always returns nil unless the rule returns an InternalError.
+func (r WarnRule[T]) Validate(value T) error {
+	return r.ValidateWithContext(context.TODO(), value)
+}
+
+// ValidateWithContext checks the given value, and records the error of the rule as a warning
+// in the Warnings carried by the context.
+func (r WarnRule[T]) ValidateWithContext(ctx context.Context, value T) error {
+	err := validateRule(ctx, r.rule, value)
+	if err == nil || isInternalError(err) {
+		return err
+	}
+	if w := warningsFromContext(ctx); w != nil {
+		err = Translate(ctx, err)
+		if isKeyPath(ctx) {
+			err = KeyError{Err: err}
+		}
+		var list []FlatError
+		flattenError(pathFromContext(ctx), err, &list)
+		w.mu.Lock()
+		w.warnings = append(w.warnings, list...)
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// CollectWarnings returns a copy of the context that collects the warnings reported by Warn rules.
+// When a value is validated with the returned context, each warning is recorded in the returned Warnings
+// with the path of the value it is about. For example,
+//
+//	ctx, warnings := kv.CollectWarnings(r.Context())
+//	if err := kv.ValidateWithContext(ctx, &req); err != nil {
+//	    ...
+//	}
+//	for _, w := range warnings.List() {
+//	    rw.Header().Add("Warning", w.Path.String()+": "+w.Message)
+//	}
+func CollectWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// List returns the warnings recorded so far, in the order they were recorded.
+func (w *Warnings) List() []FlatError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.warnings)
+}
+
+// warningsFromContext returns the Warnings collecting the warnings of the validation, if any.
+func warningsFromContext(ctx context.Context) *Warnings {
+	if ctx == nil {
+		return nil
+	}
+	w, _ := ctx.Value(warningsKey{}).(*Warnings)
+	return w
+}
+
+func (r WarnRule[T]) ruleLists() []namedRules {
+	return []namedRules{{rules: []any{r.rule}}}
+}
+
+func (r WarnRule[T]) referencesFields() bool {
+	return ruleReferencesFields(r.rule)
+}
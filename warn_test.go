@@ -0,0 +1,80 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khatibomar/kv/internal/assert"
+)
+
+type warnAccount struct {
+	Password string            `json:"password"`
+	Nickname string            `json:"nickname"`
+	Labels   map[string]string `json:"labels"`
+	Age      int               `json:"age"`
+}
+
+func (a *warnAccount) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, a,
+		Field(&a.Password, Required, Warn(Length(12, 0).Error("is weak")), Length(8, 0)),
+		Field(&a.Nickname, Warn(Not(Required).Error("is deprecated"))),
+		Field(&a.Labels, Map(
+			Key("team", Warn(Required)),
+		).AllowExtraKeys()),
+		Field(&a.Age, Warn(Any(Min(18)))),
+	)
+}
+
+func warningMessages(w *Warnings) []any {
+	var messages []any
+	for _, warning := range w.List() {
+		messages = append(messages, warning.Path.String()+": "+warning.Message)
+	}
+	return messages
+}
+
+func TestWarn(t *testing.T) {
+	ctx, warnings := CollectWarnings(context.Background())
+	a := warnAccount{Password: "secret12", Nickname: "bob", Labels: map[string]string{"team": ""}, Age: 16}
+	assert.NoError(t, ValidateWithContext(ctx, &a))
+	assert.Equal(t, []any{
+		"password: is weak",
+		"nickname: is deprecated",
+		`labels.team: cannot be blank`,
+		"age: must be no less than 18",
+	}, warningMessages(warnings))
+	assert.Equal(t, "validation_required", warnings.List()[2].Code)
+
+	// the rules following a warning are still validated
+	ctx, warnings = CollectWarnings(context.Background())
+	a = warnAccount{Password: "secret", Age: 16}
+	assert.EqualError(t, ValidateWithContext(ctx, &a), "password: the length must be no less than 8.")
+	assert.Equal(t, []any{"password: is weak", "age: must be no less than 18"}, warningMessages(warnings))
+
+	// no collector
+	assert.NoError(t, Validate("a", Warn(Length(2, 0))))
+	assert.NoError(t, Warn(Length(2, 0)).Validate("a"))
+
+	// internal errors are returned
+	err := Warn(By(func(any) error { return NewInternalError(errors.New("boom")) })).Validate("a")
+	assert.EqualError(t, err, "boom")
+}
+
+func TestWarnTyped(t *testing.T) {
+	rules := Struct[warnAccount](
+		FieldOf("age", func(a *warnAccount) *int { return &a.Age }, Warn(Min(18)), Max(150)),
+	)
+	ctx, warnings := CollectWarnings(WithLocale(context.Background(), "fr"))
+	a := warnAccount{Age: 16}
+	assert.NoError(t, rules.ValidateWithContext(ctx, &a))
+	assert.Equal(t, 1, len(warnings.List()))
+	assert.Equal(t, "/age", warnings.List()[0].Path.Pointer())
+	assert.Equal(t, "doit être supérieur ou égal à 18", warnings.List()[0].Message)
+
+	// nested errors are flattened
+	ctx, warnings = CollectWarnings(context.Background())
+	m := map[string]any{"name": ""}
+	assert.NoError(t, ValidateWithContext(ctx, m, Warn(Map(Key("name", Required)))))
+	assert.Equal(t, []any{"name: cannot be blank"}, warningMessages(warnings))
+}
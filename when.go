@@ -5,7 +5,45 @@ import "context"
 // When returns a validation rule that executes the given list of rules when the condition is true.
 func When(condition bool, rules ...Rule[any]) WhenRule {
 	return WhenRule{
-		condition: condition,
+		condition: fixedCondition(condition),
+		rules:     rules,
+		elseRules: []Rule[any]{},
+	}
+}
+
+// WhenFunc returns a validation rule that executes the given list of rules when the given function returns true.
+// Unlike When, the condition is evaluated each time a value is validated, with the context of the validation,
+// so that the rule can be declared once and reused. For example,
+//
+//	var shippingRules = kv.WhenFunc(isShipping, kv.Required, kv.Length(5, 100))
+func WhenFunc(f func(ctx context.Context) bool, rules ...Rule[any]) WhenRule {
+	return WhenRule{
+		condition: funcCondition(f),
+		rules:     rules,
+		elseRules: []Rule[any]{},
+	}
+}
+
+// WhenValue returns a validation rule that executes the given list of rules when the given function returns true
+// for the value being validated. The condition is evaluated each time a value is validated.
+func WhenValue(f func(value any) bool, rules ...Rule[any]) WhenRule {
+	return WhenRule{
+		condition: valueCondition(f),
+		rules:     rules,
+		elseRules: []Rule[any]{},
+	}
+}
+
+// WhenField returns a validation rule that executes the given list of rules when the given function returns true
+// for the value of another field. The other field must be specified as a pointer to a field of the struct being
+// validated by ValidateStruct, like EqualTo. For example,
+//
+//	kv.Field(&o.Address, kv.WhenField(&o.Delivery, func(v any) bool { return v == "ship" }, kv.Required))
+//
+// If the other field cannot be found, an InternalError is returned.
+func WhenField(fieldPtr any, f func(value any) bool, rules ...Rule[any]) WhenRule {
+	return WhenRule{
+		condition: fieldCondition(fieldPtr, f),
 		rules:     rules,
 		elseRules: []Rule[any]{},
 	}
@@ -13,7 +51,7 @@ func When(condition bool, rules ...Rule[any]) WhenRule {
 
 // WhenRule is a validation rule that executes the given list of rules when the condition is true.
 type WhenRule struct {
-	condition bool
+	condition condition
 	rules     []Rule[any]
 	elseRules []Rule[any]
 }
@@ -25,17 +63,21 @@ func (r WhenRule) Validate(value any) error {
 
 // ValidateWithContext checks if the condition is true and if so, it validates the value using the specified rules.
 func (r WhenRule) ValidateWithContext(ctx context.Context, value any) error {
-	if r.condition {
+	met, err := r.condition.met(ctx, value)
+	if err != nil {
+		return err
+	}
+	if met {
 		if ctx == nil {
 			return Validate(value, r.rules...)
 		}
-		return ValidateWithContext(ctx, value, r.rules...)
+		return validateWithContext(ctx, value, r.rules...)
 	}
 
 	if ctx == nil {
 		return Validate(value, r.elseRules...)
 	}
-	return ValidateWithContext(ctx, value, r.elseRules...)
+	return validateWithContext(ctx, value, r.elseRules...)
 }
 
 // Else returns a validation rule that executes the given list of rules when the condition is false.
@@ -43,3 +85,65 @@ func (r WhenRule) Else(rules ...Rule[any]) WhenRule {
 	r.elseRules = rules
 	return r
 }
+
+func (r WhenRule) conditionFalse(ctx context.Context, value any) bool {
+	if len(r.elseRules) > 0 {
+		return false
+	}
+	met, err := r.condition.met(ctx, value)
+	return err == nil && !met
+}
+
+// condition is the condition of a rule, which is either fixed when the rule is built,
+// or evaluated each time a value is validated.
+type condition struct {
+	fixed bool
+	eval  func(ctx context.Context, value any) (bool, error)
+	// field tells if the condition reads another field of the struct being validated.
+	field bool
+}
+
+func fixedCondition(met bool) condition {
+	return condition{fixed: met}
+}
+
+func funcCondition(f func(ctx context.Context) bool) condition {
+	return condition{eval: func(ctx context.Context, _ any) (bool, error) {
+		return f(ctx), nil
+	}}
+}
+
+func valueCondition(f func(value any) bool) condition {
+	return condition{eval: func(_ context.Context, value any) (bool, error) {
+		return f(value), nil
+	}}
+}
+
+func fieldCondition(fieldPtr any, f func(value any) bool) condition {
+	return condition{eval: func(ctx context.Context, _ any) (bool, error) {
+		_, value, err := referencedField(ctx, fieldPtr)
+		if err != nil {
+			return false, err
+		}
+		return f(value), nil
+	}, field: true}
+}
+
+// met checks if the condition is true for the given value.
+func (c condition) met(ctx context.Context, value any) (bool, error) {
+	if c.eval == nil {
+		return c.fixed, nil
+	}
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return c.eval(ctx, value)
+}
+
+func (r WhenRule) ruleLists() []namedRules {
+	return []namedRules{{rules: anyRules(r.rules)}, {rules: anyRules(r.elseRules)}}
+}
+
+func (r WhenRule) referencesFields() bool {
+	return r.condition.field || rulesReferenceFields(r.rules) || rulesReferenceFields(r.elseRules)
+}
@@ -98,3 +98,67 @@ func TestWhenWithContext(t *testing.T) {
 		assertError(t, test.err, err, test.tag)
 	}
 }
+
+type roleKey struct{}
+
+func isAdmin(ctx context.Context) bool {
+	return ctx.Value(roleKey{}) == "admin"
+}
+
+// declared once and reused with different contexts
+var adminRules = WhenFunc(isAdmin, Required).Else(blank())
+
+func blank() Rule[any] {
+	return By(func(value any) error {
+		if !IsEmpty(value) {
+			return errors.New("must be blank")
+		}
+		return nil
+	})
+}
+
+func TestWhenFunc(t *testing.T) {
+	admin := context.WithValue(context.Background(), roleKey{}, "admin")
+	tests := []struct {
+		tag   string
+		ctx   context.Context
+		value any
+		err   string
+	}{
+		{"t1", admin, "", "cannot be blank"},
+		{"t2", admin, "x", ""},
+		{"t3", context.Background(), "", ""},
+		{"t4", context.Background(), "x", "must be blank"},
+	}
+	for _, test := range tests {
+		err := ValidateWithContext(test.ctx, test.value, adminRules)
+		assertError(t, test.err, err, test.tag)
+	}
+	assertError(t, "must be blank", adminRules.Validate("x"), "t5")
+}
+
+func TestWhenValue(t *testing.T) {
+	rule := WhenValue(func(value any) bool { return strings.HasPrefix(value.(string), "a") }, NewStringRule(abcValidation, "wrong_abc"))
+	assertError(t, "wrong_abc", Validate("ab", rule), "t1")
+	assertError(t, "", Validate("abc", rule), "t2")
+	assertError(t, "", Validate("xyz", rule), "t3")
+}
+
+func TestWhenField(t *testing.T) {
+	type order struct {
+		Delivery string `json:"delivery"`
+		Address  string `json:"address"`
+	}
+	shipped := func(value any) bool { return value == "ship" }
+
+	o := order{Delivery: "ship"}
+	err := ValidateStruct(&o, Field(&o.Address, WhenField(&o.Delivery, shipped, Required)))
+	assertError(t, "address: cannot be blank.", err, "t1")
+	o = order{Delivery: "pickup"}
+	err = ValidateStruct(&o, Field(&o.Address, WhenField(&o.Delivery, shipped, Required)))
+	assertError(t, "", err, "t2")
+
+	// the field is not in the struct being validated
+	err = Validate("", WhenField(&o.Delivery, shipped, Required))
+	assertError(t, ErrFieldReference.Error(), err, "t3")
+}